@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/s3rj1k/yafp/pkg/archive"
+	"github.com/s3rj1k/yafp/pkg/feedhlp"
+	"github.com/s3rj1k/yafp/pkg/upstream"
+)
+
+const (
+	archiveMaxItems      = 1000
+	archiveMaxSources    = 10000
+	archiveMaxIdle       = 30 * 24 * time.Hour
+	archivePruneInterval = time.Hour
+
+	archiveKindFeed    = "feed"
+	archiveKindTG      = "tg"
+	archiveKindScrape  = "scrape"
+	archiveKindJSON    = "json"
+	archiveKindSitemap = "sitemap"
+)
+
+//nolint:gochecknoglobals // item archive, nil when disabled
+var (
+	itemArchive *archive.Archive
+)
+
+// archiveFeed records source items before rules are applied and extends feed with archived
+// items up to history depth, only sources requested with history are archived.
+// Archive failure is logged and fresh feed is served.
+func archiveFeed(kind, source string, opts *upstream.Options, feed *feedhlp.Feed, history int) {
+	if itemArchive == nil || history <= 0 {
+		return
+	}
+
+	key := fmt.Sprintf("%s %s %s", kind, opts, source)
+
+	if err := itemArchive.Merge(key, feed, history); err != nil {
+		fmt.Fprintf(gin.DefaultErrorWriter, "[Archive] %s: %s\n", key, err.Error())
+	}
+}
+
+// pruneArchive removes idle archive sources with provided interval.
+func pruneArchive(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := itemArchive.Prune(); err != nil {
+			fmt.Fprintf(gin.DefaultErrorWriter, "[Archive] prune: %s\n", err.Error())
+		}
+	}
+}
+
+// processItems records source items in archive (archived items extend feed up to history depth),
+// then applies rules mutator (may be nil), archive keeps items before rules are applied.
+func processItems(
+	feed *feedhlp.Feed, kind, source string, opts *upstream.Options,
+	history int, mutator func(item *feedhlp.Item) *feedhlp.Item,
+) {
+	archiveFeed(kind, source, opts, feed, history)
+
+	if mutator != nil {
+		feedhlp.MutateItems(feed, mutator)
+	}
+}
+
+// keepItem is mutator that keeps items unchanged.
+func keepItem(item *feedhlp.Item) *feedhlp.Item {
+	return item
+}
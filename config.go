@@ -0,0 +1,127 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/s3rj1k/yafp/pkg/upstream"
+)
+
+var configNameRegExp = regexp.MustCompile("^[a-zA-Z0-9_-]{1,64}$")
+
+//nolint:gochecknoglobals // global configuration loaded from file
+var (
+	config = new(Config)
+)
+
+type Config struct {
+	Profiles  map[string]*Profile          `json:"profiles"`
+	Upstreams map[string]*upstream.Options `json:"upstreams"`
+	Proxies   map[string]string            `json:"proxies"`
+	Scrapers  map[string]*Scrape           `json:"scrapers"`
+	JSON      map[string]*JSON             `json:"json"`
+}
+
+// loadConfig reads JSON configuration file and validates every profile
+// with the same rules that are used for URL query parameters.
+func loadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read config error: %w", err)
+	}
+
+	cfg := new(Config)
+
+	if err = json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("parse config error: %w", err)
+	}
+
+	for host, val := range cfg.Proxies {
+		if host == "" {
+			return nil, fmt.Errorf("undefined proxy host")
+		}
+
+		if _, err = upstream.ParseProxyURL(val); err != nil {
+			return nil, fmt.Errorf("invalid proxy for %q: %w", host, err)
+		}
+	}
+
+	for name, opts := range cfg.Upstreams {
+		if !configNameRegExp.MatchString(name) {
+			return nil, fmt.Errorf("invalid upstream name: %q", name)
+		}
+
+		if opts == nil {
+			return nil, fmt.Errorf("undefined upstream: %q", name)
+		}
+
+		if err = binding.Validator.ValidateStruct(opts); err != nil {
+			return nil, fmt.Errorf("invalid upstream %q: %w", name, err)
+		}
+
+		opts.Name = name
+	}
+
+	for name, profile := range cfg.Profiles {
+		if !configNameRegExp.MatchString(name) {
+			return nil, fmt.Errorf("invalid profile name: %q", name)
+		}
+
+		if profile == nil {
+			return nil, fmt.Errorf("undefined profile: %q", name)
+		}
+
+		if err = binding.Validator.ValidateStruct(profile); err != nil {
+			return nil, fmt.Errorf("invalid profile %q: %w", name, err)
+		}
+
+		if _, ok := cfg.Upstreams[profile.Upstream]; profile.Upstream != "" && !ok {
+			return nil, fmt.Errorf("profile %q: unknown upstream: %q", name, profile.Upstream)
+		}
+	}
+
+	for name, scrape := range cfg.Scrapers {
+		if !configNameRegExp.MatchString(name) {
+			return nil, fmt.Errorf("invalid scraper name: %q", name)
+		}
+
+		if scrape == nil {
+			return nil, fmt.Errorf("undefined scraper: %q", name)
+		}
+
+		if err = binding.Validator.ValidateStruct(scrape); err != nil {
+			return nil, fmt.Errorf("invalid scraper %q: %w", name, err)
+		}
+
+		if _, ok := cfg.Upstreams[scrape.Upstream]; scrape.Upstream != "" && !ok {
+			return nil, fmt.Errorf("scraper %q: unknown upstream: %q", name, scrape.Upstream)
+		}
+	}
+
+	for name, source := range cfg.JSON {
+		if !configNameRegExp.MatchString(name) {
+			return nil, fmt.Errorf("invalid JSON source name: %q", name)
+		}
+
+		if source == nil {
+			return nil, fmt.Errorf("undefined JSON source: %q", name)
+		}
+
+		if err = binding.Validator.ValidateStruct(source); err != nil {
+			return nil, fmt.Errorf("invalid JSON source %q: %w", name, err)
+		}
+
+		if _, ok := cfg.Upstreams[source.Upstream]; source.Upstream != "" && !ok {
+			return nil, fmt.Errorf("JSON source %q: unknown upstream: %q", name, source.Upstream)
+		}
+
+		if source.FeedTitle == "" {
+			source.FeedTitle = name
+		}
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/s3rj1k/yafp/pkg/discover"
+	"github.com/s3rj1k/yafp/pkg/validation"
+)
+
+type Discover struct {
+	URL      string `form:"url" binding:"required,url"`
+	Upstream string `form:"upstream"`
+}
+
+// handleDiscover lists feeds advertised by HTML page, the best candidate goes first.
+func handleDiscover(c *gin.Context) {
+	cfg := new(Discover)
+
+	if err := c.BindQuery(cfg); err != nil {
+		c.String(http.StatusBadRequest, "%s\n",
+			validation.ErrorResponse(err, strings.NewReplacer(
+				"URL", "url",
+				"Upstream", "upstream",
+			)),
+		)
+
+		return
+	}
+
+	opts, ok := bindUpstream(c, cfg.Upstream)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), feedFetchTimeoutSeconds*time.Second)
+	defer cancel()
+
+	doc, err := fetchDocument(ctx, cfg.URL, c.Request.UserAgent(), opts)
+	if err != nil {
+		_ = c.Error(err)
+
+		c.String(http.StatusBadGateway, "%d Bad Gateway\n", http.StatusBadGateway)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, discover.Find(doc, doc.Url))
+}
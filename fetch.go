@@ -0,0 +1,300 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/jellydator/ttlcache/v3"
+	"github.com/jlelse/feeds"
+	"github.com/mmcdole/gofeed"
+	"github.com/s3rj1k/yafp/pkg/discover"
+	"github.com/s3rj1k/yafp/pkg/feedhlp"
+	"github.com/s3rj1k/yafp/pkg/sitemap"
+	"github.com/s3rj1k/yafp/pkg/tgscrapper"
+	"github.com/s3rj1k/yafp/pkg/upstream"
+)
+
+const (
+	feedFetchTimeoutSeconds = 90
+
+	maxDocumentSizeBytes = 5 << 20
+	maxImageSizeBytes    = 10 << 20
+	maxSitemapSizeBytes  = 50 << 20
+
+	upstreamCacheRecordTTL = 24 * time.Hour
+	pageCacheRecordTTL     = 24 * time.Hour
+
+	discoverCacheKeyPrefix = "DISCOVER:"
+)
+
+//nolint:gochecknoglobals // shared client for outbound requests, see `newHTTPClient`
+var (
+	httpClient = http.DefaultClient
+)
+
+//nolint:gochecknoglobals // raster image types served by image proxy, scriptable types (e.g. SVG) are rejected
+var (
+	imageContentTypes = map[string]struct{}{
+		"image/apng":               {},
+		"image/avif":               {},
+		"image/bmp":                {},
+		"image/gif":                {},
+		"image/jpeg":               {},
+		"image/png":                {},
+		"image/vnd.microsoft.icon": {},
+		"image/webp":               {},
+		"image/x-icon":             {},
+	}
+)
+
+// fetchFeed downloads and parses upstream feed, upstream options may be nil.
+func fetchFeed(ctx context.Context, feedURL, userAgent string, opts *upstream.Options) (*gofeed.Feed, error) {
+	fp := feedhlp.NewParser()
+
+	fp.UserAgent = userAgent
+	fp.Client = httpClient
+
+	if item := cache.Get(discoverCacheKeyPrefix + feedURL); item != nil {
+		if val, ok := item.Value().(string); ok {
+			feedURL = val
+		}
+	}
+
+	feed, err := upstream.ParseURL(ctx, cache, fp, feedURL, opts, upstreamCacheRecordTTL, maxDocumentSizeBytes)
+
+	var docErr *upstream.DocumentError
+
+	if errors.As(err, &docErr) {
+		// page is not a feed, follow the best feed advertised by page
+		if candidate, ok := discoverFeed(docErr); ok && candidate.URL != feedURL {
+			feed, err = upstream.ParseURL(ctx, cache, fp, candidate.URL, opts, upstreamCacheRecordTTL, maxDocumentSizeBytes)
+			if err == nil {
+				_ = cache.Set(discoverCacheKeyPrefix+feedURL, candidate.URL, upstreamCacheRecordTTL)
+			}
+		}
+	}
+
+	if err != nil {
+		return nil, err //nolint:wrapcheck // pass gofeed error unwrapped, see `feedhlp.HTTPErrorResponse`
+	}
+
+	if feed == nil {
+		return nil, feedhlp.ErrUndefinedFeed
+	}
+
+	return feed, nil
+}
+
+// discoverFeed returns the best feed advertised by HTML page that is not a feed.
+func discoverFeed(docErr *upstream.DocumentError) (discover.Candidate, bool) {
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(docErr.Body))
+	if err != nil {
+		return discover.Candidate{}, false
+	}
+
+	candidates := discover.Find(doc, docErr.URL)
+	if len(candidates) == 0 {
+		return discover.Candidate{}, false
+	}
+
+	return candidates[0], true
+}
+
+// fetchTGChannel scrapes telegram channel, name needs to be validated.
+func fetchTGChannel(ctx context.Context, name, userAgent string, opts *upstream.Options) (*feedhlp.Feed, error) {
+	data := tgscrapper.NewMessages(name)
+
+	if err := tgscrapper.Worker(ctx, httpClient, data, userAgent, opts.Prepare); err != nil {
+		return nil, err //nolint:wrapcheck // pass tgscrapper error unwrapped
+	}
+
+	feed := new(feedhlp.Feed)
+
+	feed.Title = data.ChannelTitle
+	feed.Link = &feeds.Link{
+		Href: data.ChannelLink,
+	}
+	feed.Description = data.ChannelDescription
+	feed.Updated = data.GenerationTime
+	feed.Created = data.OldestMessageDate
+
+	feed.Items = make([]*feedhlp.Item, 0, len(data.Items))
+
+	for _, el := range data.Items {
+		if el == nil {
+			continue
+		}
+
+		item := new(feedhlp.Item)
+
+		item.Id = strconv.Itoa(el.ID)
+		item.Title = el.Title
+		item.Description = el.Body
+		item.Link = &feeds.Link{
+			Href: el.Link,
+		}
+
+		if !el.DateTime.IsZero() {
+			item.Created = el.DateTime
+			item.Updated = el.DateTime
+		}
+
+		item.Author = &feeds.Author{
+			Name: el.Author,
+		}
+
+		feed.Items = append(feed.Items, item)
+	}
+
+	return feed, nil
+}
+
+// fetch runs GET request with user agent, accepted content type (may be empty) and upstream
+// options (may be nil), response status other than `200 OK` is an error, caller closes response body.
+func fetch(ctx context.Context, rawURL, userAgent, accept string, opts *upstream.Options) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, http.NoBody)
+	if err != nil {
+		return nil, fmt.Errorf("prepare request error: %w", err)
+	}
+
+	if userAgent != "" {
+		req.Header.Set("User-Agent", userAgent)
+	}
+
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+
+	opts.Prepare(req)
+
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("run request error: %w", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		_ = res.Body.Close()
+
+		return nil, fmt.Errorf("unexpected status code: %d", res.StatusCode)
+	}
+
+	return res, nil
+}
+
+// fetchDocument downloads and parses HTML page.
+func fetchDocument(ctx context.Context, pageURL, userAgent string, opts *upstream.Options) (*goquery.Document, error) {
+	res, err := fetch(ctx, pageURL, userAgent, "", opts)
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+
+	doc, err := goquery.NewDocumentFromReader(io.LimitReader(res.Body, maxDocumentSizeBytes))
+	if err != nil {
+		return nil, fmt.Errorf("read response error: %w", err)
+	}
+
+	doc.Url = res.Request.URL
+
+	return doc, nil
+}
+
+// getPageValue returns value extracted from linked page, results (including failures
+// as empty string) are cached per key prefix, options and link, so repeated polls do not refetch pages.
+func getPageValue(
+	ctx context.Context, keyPrefix, link, userAgent string, opts *upstream.Options,
+	extract func(doc *goquery.Document) string,
+) string {
+	key := fmt.Sprintf("%s%s %s", keyPrefix, opts, link)
+
+	if item := cache.Get(key); item != nil {
+		if val, ok := item.Value().(string); ok {
+			return val
+		}
+	}
+
+	doc, err := fetchDocument(ctx, link, userAgent, opts)
+	if err != nil {
+		// failure is cached for shorter time, canceled requests are retried
+		if ctx.Err() == nil {
+			_ = cache.Set(key, "", ttlcache.DefaultTTL)
+		}
+
+		return ""
+	}
+
+	val := extract(doc)
+
+	_ = cache.Set(key, val, pageCacheRecordTTL)
+
+	return val
+}
+
+// fetchImage downloads image, response that is not a raster image or is too large is rejected.
+func fetchImage(ctx context.Context, imageURL, userAgent string) (contentType string, data []byte, err error) {
+	res, err := fetch(ctx, imageURL, userAgent, "", nil)
+	if err != nil {
+		return "", nil, err
+	}
+
+	defer res.Body.Close()
+
+	contentType, _, err = mime.ParseMediaType(res.Header.Get("Content-Type"))
+	if _, ok := imageContentTypes[contentType]; err != nil || !ok {
+		return "", nil, fmt.Errorf("unexpected content type: %q", res.Header.Get("Content-Type"))
+	}
+
+	data, err = io.ReadAll(io.LimitReader(res.Body, maxImageSizeBytes+1))
+	if err != nil {
+		return "", nil, fmt.Errorf("read response error: %w", err)
+	}
+
+	if len(data) > maxImageSizeBytes {
+		return "", nil, fmt.Errorf("image is larger than %d bytes", maxImageSizeBytes)
+	}
+
+	return contentType, data, nil
+}
+
+// fetchJSON downloads and decodes JSON document, numbers are decoded as `json.Number`.
+func fetchJSON(ctx context.Context, docURL, userAgent string, opts *upstream.Options) (any, error) {
+	res, err := fetch(ctx, docURL, userAgent, "application/json", opts)
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+
+	var doc any
+
+	dec := json.NewDecoder(io.LimitReader(res.Body, maxDocumentSizeBytes))
+	dec.UseNumber()
+
+	if err = dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("read response error: %w", err)
+	}
+
+	return doc, nil
+}
+
+// fetchSitemap downloads and parses sitemap or sitemap index.
+func fetchSitemap(ctx context.Context, sitemapURL, userAgent string, opts *upstream.Options) (*sitemap.Sitemap, error) {
+	res, err := fetch(ctx, sitemapURL, userAgent, "", opts)
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+
+	return sitemap.Parse(io.LimitReader(res.Body, maxSitemapSizeBytes)) //nolint:wrapcheck // pass sitemap error unwrapped
+}
@@ -2,11 +2,19 @@ package main
 
 import (
 	"flag"
+	"time"
 )
 
 //nolint:gochecknoglobals // CLI configuration flags
 var (
 	flagBindAddress string
+	flagConfigPath  string
+	flagProxyURL    string
+	flagImageKey    string
+	flagPublicURL   string
+	flagArchiveDir  string
+
+	flagWebSubInterval time.Duration
 
 	flagVersion bool
 )
@@ -14,6 +22,12 @@ var (
 func parseInputConfiguration() error {
 	flag.BoolVar(&flagVersion, "version", false, "Show build information and exit")
 	flag.StringVar(&flagBindAddress, "bind-address", ":8080", "Address for HTTP server bind")
+	flag.StringVar(&flagConfigPath, "config", "", "Path to JSON configuration file with named profiles")
+	flag.StringVar(&flagProxyURL, "proxy", "", "Proxy URL (http, https or socks5) for all outbound requests")
+	flag.StringVar(&flagImageKey, "image-key", "", "Key for signing image proxy URLs (random when empty)")
+	flag.StringVar(&flagPublicURL, "public-url", "", "Public base URL of the service (request host when empty)")
+	flag.StringVar(&flagArchiveDir, "archive-dir", "", "Directory of persistent item archive (archive is disabled when empty)")
+	flag.DurationVar(&flagWebSubInterval, "websub-interval", 0, "WebSub hub topics poll interval (hub is disabled when zero)")
 
 	flag.Parse()
 
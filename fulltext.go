@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/s3rj1k/yafp/pkg/feedhlp"
+	"github.com/s3rj1k/yafp/pkg/readability"
+	"github.com/s3rj1k/yafp/pkg/upstream"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	fullTextCacheKeyPrefix = "FULLTEXT:"
+
+	fullTextConcurrency = 4
+)
+
+// getFullText returns extracted main content of linked page, see `getPageValue`.
+func getFullText(ctx context.Context, link, userAgent string, opts *upstream.Options) string {
+	return getPageValue(ctx, fullTextCacheKeyPrefix, link, userAgent, opts, func(doc *goquery.Document) string {
+		out, err := readability.Extract(doc, doc.Url)
+		if err != nil {
+			return ""
+		}
+
+		return out
+	})
+}
+
+// populateFullText replaces items content with extracted main content of linked pages.
+func populateFullText(ctx context.Context, feed *feedhlp.Feed, userAgent string, opts *upstream.Options) {
+	g := new(errgroup.Group)
+
+	g.SetLimit(fullTextConcurrency)
+
+	for _, el := range feed.Items {
+		item := el
+
+		if item.Link == nil || item.Link.Href == "" {
+			continue
+		}
+
+		g.Go(func() error {
+			if content := getFullText(ctx, item.Link.Href, userAgent, opts); content != "" {
+				item.Content = content
+			}
+
+			return nil
+		})
+	}
+
+	_ = g.Wait()
+}
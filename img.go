@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/s3rj1k/yafp/pkg/imgproxy"
+	"github.com/s3rj1k/yafp/pkg/validation"
+)
+
+const (
+	imageKeySizeBytes = 32
+
+	imageCacheControl = "public, max-age=86400"
+
+	// proxied responses are served from service origin, they must never be rendered as documents
+	imageContentSecurityPolicy = "default-src 'none'; sandbox"
+)
+
+//nolint:gochecknoglobals // image proxy URLs signing key, see `initImageKey`
+var (
+	imageKey []byte
+)
+
+type Image struct {
+	URL       string `form:"url" binding:"required,url"`
+	Signature string `form:"sig" binding:"required"`
+}
+
+// initImageKey sets signing key, random key is generated when key is empty,
+// in that case signed URLs are valid until restart.
+func initImageKey(key string) error {
+	if key != "" {
+		imageKey = []byte(key)
+
+		return nil
+	}
+
+	imageKey = make([]byte, imageKeySizeBytes)
+
+	_, err := rand.Read(imageKey)
+
+	return err //nolint:wrapcheck // pass crypto/rand error unwrapped
+}
+
+// publicBaseURL returns public URL of the service, request host is used when it is not set.
+func publicBaseURL(c *gin.Context) string {
+	if base := strings.TrimSuffix(flagPublicURL, "/"); base != "" {
+		return base
+	}
+
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+
+	return scheme + "://" + c.Request.Host
+}
+
+// imageProxy returns function that rewrites image URLs to signed `/img` URLs.
+func imageProxy(c *gin.Context) func(src string) string {
+	endpoint := publicBaseURL(c) + "/img"
+
+	return func(src string) string {
+		return imgproxy.URL(endpoint, imageKey, src)
+	}
+}
+
+func handleImage(c *gin.Context) {
+	c.Header("Content-Security-Policy", imageContentSecurityPolicy)
+	c.Header("X-Content-Type-Options", "nosniff")
+
+	cfg := new(Image)
+
+	if err := c.BindQuery(cfg); err != nil {
+		c.String(http.StatusBadRequest, "%s\n",
+			validation.ErrorResponse(err, strings.NewReplacer(
+				"URL", imgproxy.QueryParamURL,
+				"Signature", imgproxy.QueryParamSignature,
+			)),
+		)
+
+		return
+	}
+
+	if !imgproxy.Verify(imageKey, cfg.URL, cfg.Signature) {
+		c.String(http.StatusForbidden, "%d Forbidden\n", http.StatusForbidden)
+
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), feedFetchTimeoutSeconds*time.Second)
+	defer cancel()
+
+	contentType, data, err := fetchImage(ctx, cfg.URL, c.Request.UserAgent())
+	if err != nil {
+		_ = c.Error(err)
+
+		c.String(http.StatusBadGateway, "%d Bad Gateway\n", http.StatusBadGateway)
+
+		return
+	}
+
+	c.Header("Cache-Control", imageCacheControl)
+	c.Data(http.StatusOK, contentType, data)
+}
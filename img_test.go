@@ -0,0 +1,82 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/s3rj1k/yafp/pkg/imgproxy"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandleImage(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, initImageKey("test"))
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/image.svg":
+			w.Header().Set("Content-Type", "image/svg+xml")
+			_, _ = w.Write([]byte(`<svg xmlns="http://www.w3.org/2000/svg"><script>alert(1)</script></svg>`))
+		case "/image.png":
+			w.Header().Set("Content-Type", "image/PNG; charset=binary")
+			_, _ = w.Write([]byte("\x89PNG\r\n\x1a\n"))
+		default:
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = w.Write([]byte("<html></html>"))
+		}
+	}))
+	t.Cleanup(ts.Close)
+
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.GET("/img", handleImage)
+
+	tests := []struct {
+		name        string
+		path        string
+		status      int
+		contentType string
+	}{
+		{
+			name:        "raster image",
+			path:        "/image.png",
+			status:      http.StatusOK,
+			contentType: "image/png",
+		},
+		{
+			name:   "svg image",
+			path:   "/image.svg",
+			status: http.StatusBadGateway,
+		},
+		{
+			name:   "not an image",
+			path:   "/page.html",
+			status: http.StatusBadGateway,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, imgproxy.URL("/img", imageKey, ts.URL+tt.path), http.NoBody)
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+			assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+			assert.Equal(t, imageContentSecurityPolicy, w.Header().Get("Content-Security-Policy"))
+
+			if tt.contentType != "" {
+				assert.Equal(t, tt.contentType, w.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
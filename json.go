@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jlelse/feeds"
+	"github.com/s3rj1k/yafp/pkg/feedhlp"
+	"github.com/s3rj1k/yafp/pkg/jsonmapper"
+	"github.com/s3rj1k/yafp/pkg/upstream"
+	"github.com/s3rj1k/yafp/pkg/validation"
+)
+
+type JSON struct {
+	URL       string `form:"url" json:"url" binding:"required,url"`
+	FeedTitle string `form:"feed_title" json:"feedTitle"`
+	Upstream  string `form:"upstream" json:"upstream"`
+
+	jsonmapper.Mapping
+	Rules
+	FeedOptions
+}
+
+type JSONSourceName struct {
+	Name string `uri:"name" binding:"required"`
+}
+
+func jsonProperURLQueryParamsName() *strings.Replacer {
+	return properURLQueryParamsName(
+		"FeedTitle", "feed_title",
+		"URL", "url",
+		"Upstream", "upstream",
+		"Items", "items",
+		"Title", "title",
+		"Link", "link",
+		"Date", "date",
+		"Body", "body",
+		"ID", "id",
+	)
+}
+
+func handleJSON(c *gin.Context) {
+	cfg := new(JSON)
+
+	if err := c.BindQuery(cfg); err != nil {
+		c.String(http.StatusBadRequest, "%s\n",
+			validation.ErrorResponse(err, jsonProperURLQueryParamsName()),
+		)
+
+		return
+	}
+
+	serveJSON(c, cfg)
+}
+
+// handleJSONSource serves named JSON source from configuration.
+func handleJSONSource(c *gin.Context) {
+	cfg := new(JSONSourceName)
+
+	if err := c.BindUri(cfg); err != nil {
+		c.String(http.StatusBadRequest, "invalid JSON source name\n")
+
+		return
+	}
+
+	source, ok := config.JSON[cfg.Name]
+	if !ok || source == nil {
+		c.String(http.StatusNotFound, "%d Not Found\n", http.StatusNotFound)
+
+		return
+	}
+
+	serveJSON(c, source)
+}
+
+func serveJSON(c *gin.Context, cfg *JSON) {
+	opts, ok := bindUpstream(c, cfg.Upstream)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), feedFetchTimeoutSeconds*time.Second)
+	defer cancel()
+
+	feedOut, err := fetchJSONFeed(ctx, cfg, c.Request.UserAgent(), opts)
+	if err != nil {
+		c.String(http.StatusServiceUnavailable,
+			"%d Unable to map JSON document", http.StatusServiceUnavailable)
+
+		_ = c.Error(err)
+
+		return
+	}
+
+	processItems(feedOut, archiveKindJSON, fmt.Sprintf("%s %v", cfg.URL, cfg.Mapping), opts,
+		cfg.History, cfg.Rules.Mutator())
+
+	serveFeed(c, feedOut, &cfg.FeedOptions, feedhlp.ContentTypeRSS, nil)
+}
+
+// fetchJSONFeed downloads JSON document and maps it to feed, relative item
+// links are resolved against document URL, item ID falls back to link.
+func fetchJSONFeed(ctx context.Context, cfg *JSON, userAgent string, opts *upstream.Options) (*feedhlp.Feed, error) {
+	doc, err := fetchJSON(ctx, cfg.URL, userAgent, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := jsonmapper.Parse(doc, &cfg.Mapping)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // pass jsonmapper error unwrapped
+	}
+
+	base, err := url.Parse(cfg.URL)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // URL is validated
+	}
+
+	feed := new(feedhlp.Feed)
+
+	feed.Title = cfg.FeedTitle
+	if feed.Title == "" {
+		feed.Title = base.Host
+	}
+
+	feed.Link = &feeds.Link{
+		Href: cfg.URL,
+	}
+	feed.Updated = time.Now().UTC()
+
+	feed.Items = make([]*feedhlp.Item, 0, len(items))
+
+	for _, el := range items {
+		item := new(feedhlp.Item)
+
+		link := el.Link
+		if link != "" {
+			if u, err := base.Parse(link); err == nil {
+				link = u.String()
+			}
+		}
+
+		item.Id = el.ID
+		if item.Id == "" {
+			item.Id = link
+		}
+
+		item.Title = el.Title
+		item.Description = el.Body
+		item.Link = &feeds.Link{
+			Href: link,
+		}
+
+		if !el.Date.IsZero() {
+			item.Created = el.Date
+			item.Updated = el.Date
+		}
+
+		feed.Items = append(feed.Items, item)
+	}
+
+	return feed, nil
+}
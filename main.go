@@ -10,6 +10,8 @@ import (
 	"github.com/gin-gonic/gin/binding"
 	"github.com/go-playground/validator/v10"
 	"github.com/jellydator/ttlcache/v3"
+	"github.com/s3rj1k/yafp/pkg/archive"
+	"github.com/s3rj1k/yafp/pkg/feedhlp"
 	"github.com/s3rj1k/yafp/pkg/gincache"
 	"github.com/s3rj1k/yafp/pkg/ratelimit"
 	"github.com/s3rj1k/yafp/pkg/vcsinfo"
@@ -52,9 +54,31 @@ func main() {
 			panic(err)
 		}
 
+		if err := v.RegisterValidation("filter", ValidateFilterExpression); err != nil {
+			panic(err)
+		}
+
 		if err := v.RegisterValidation("tg", ValidateTGChannelName); err != nil {
 			panic(err)
 		}
+
+		if err := v.RegisterValidation("selector", ValidateCSSSelector); err != nil {
+			panic(err)
+		}
+
+		if err := v.RegisterValidation("jsonpath", ValidateJSONPath); err != nil {
+			panic(err)
+		}
+
+		if err := v.RegisterValidation("duration", ValidateDuration); err != nil {
+			panic(err)
+		}
+
+		if err := v.RegisterValidation("date", ValidateDate); err != nil {
+			panic(err)
+		}
+
+		v.RegisterStructValidation(ValidateMute, Mute{})
 	}
 
 	router.RemoveExtraSlash = true
@@ -70,6 +94,35 @@ func main() {
 	go cache.Start()
 	defer cache.Stop()
 
+	if flagConfigPath != "" {
+		cfg, err := loadConfig(flagConfigPath)
+		if err != nil {
+			panic(err)
+		}
+
+		config = cfg
+	}
+
+	client, err := newHTTPClient(flagProxyURL, config.Proxies)
+	if err != nil {
+		panic(err)
+	}
+
+	httpClient = client
+
+	if err = initImageKey(flagImageKey); err != nil {
+		panic(err)
+	}
+
+	if flagArchiveDir != "" {
+		itemArchive, err = archive.New(flagArchiveDir, archiveMaxItems, archiveMaxSources, archiveMaxIdle)
+		if err != nil {
+			panic(err)
+		}
+
+		go pruneArchive(archivePruneInterval)
+	}
+
 	_ = router.Use(
 		gin.LoggerWithFormatter(func(param gin.LogFormatterParams) string {
 			return fmt.Sprintf("[GIN] %v | %3d | %13v | %15s | %s | %s %#v\n%s",
@@ -94,6 +147,11 @@ func main() {
 			cache,
 			ttlcache.DefaultTTL,
 			feedFetchTimeoutSeconds,
+			// explicit `format` is a part of URL, only negotiated format depends on `Accept`
+			gincache.Vary{
+				Header:    "Accept",
+				Normalize: feedhlp.NegotiateContentType,
+			},
 		),
 	)
 
@@ -111,13 +169,68 @@ func main() {
 		c.String(http.StatusNoContent, "")
 	})
 
-	_ = router.GET("/mute", handleMuteFeed)
+	_ = router.GET("/mute", advertiseWebSubHub, handleMuteFeed)
 
 	_ = router.HEAD("/tg", func(c *gin.Context) {
 		c.String(http.StatusNoContent, "")
 	})
 
-	_ = router.GET("/tg/:name", handleTG)
+	_ = router.GET("/tg/:name", advertiseWebSubHub, handleTG)
+
+	_ = router.HEAD("/merge", func(c *gin.Context) {
+		c.String(http.StatusNoContent, "")
+	})
+
+	_ = router.GET("/merge", advertiseWebSubHub, handleMerge)
+
+	_ = router.HEAD("/p", func(c *gin.Context) {
+		c.String(http.StatusNoContent, "")
+	})
+
+	_ = router.GET("/p/:name", advertiseWebSubHub, handleProfile)
+
+	_ = router.HEAD("/scrape", func(c *gin.Context) {
+		c.String(http.StatusNoContent, "")
+	})
+
+	_ = router.GET("/scrape", advertiseWebSubHub, handleScrape)
+	_ = router.GET("/scrape/:name", advertiseWebSubHub, handleScraper)
+
+	_ = router.HEAD("/json", func(c *gin.Context) {
+		c.String(http.StatusNoContent, "")
+	})
+
+	_ = router.GET("/json", advertiseWebSubHub, handleJSON)
+	_ = router.GET("/json/:name", advertiseWebSubHub, handleJSONSource)
+
+	_ = router.HEAD("/sitemap", func(c *gin.Context) {
+		c.String(http.StatusNoContent, "")
+	})
+
+	_ = router.GET("/sitemap", advertiseWebSubHub, handleSitemap)
+
+	_ = router.GET("/opml", handleOPMLExport)
+	_ = router.POST("/opml", handleOPMLImport)
+
+	_ = router.HEAD("/img", func(c *gin.Context) {
+		c.String(http.StatusNoContent, "")
+	})
+
+	_ = router.GET("/img", handleImage)
+
+	_ = router.HEAD("/discover", func(c *gin.Context) {
+		c.String(http.StatusNoContent, "")
+	})
+
+	_ = router.GET("/discover", handleDiscover)
+
+	_ = router.POST("/websub", handleWebSub)
+
+	if flagWebSubInterval > 0 {
+		if err := initWebSubHub(router, flagWebSubInterval); err != nil {
+			panic(err)
+		}
+	}
 
 	if err := router.Run(flagBindAddress); err != nil {
 		panic(err)
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/s3rj1k/yafp/pkg/feedhlp"
+	"github.com/s3rj1k/yafp/pkg/validation"
+	"golang.org/x/sync/errgroup"
+)
+
+type Merge struct {
+	FeedURLs []string `form:"feed_url" json:"feedURLs" binding:"required_without=TG,max=20,dive,url"`
+	TG       []string `form:"tg" json:"tg" binding:"required_without=FeedURLs,max=20,dive,tg"`
+	Title    string   `form:"title" json:"title"`
+	Upstream string   `form:"upstream" json:"upstream"`
+
+	Rules
+	FeedOptions
+}
+
+func mergeProperURLQueryParamsName() *strings.Replacer {
+	return properURLQueryParamsName(
+		"FeedURLs", "feed_url",
+		"TG", "tg",
+		"Title", "title",
+		"Upstream", "upstream",
+	)
+}
+
+func handleMerge(c *gin.Context) {
+	cfg := new(Merge)
+
+	if err := c.BindQuery(cfg); err != nil {
+		c.String(http.StatusBadRequest, "%s\n",
+			validation.ErrorResponse(err, mergeProperURLQueryParamsName()),
+		)
+
+		return
+	}
+
+	opts, ok := bindUpstream(c, cfg.Upstream)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), feedFetchTimeoutSeconds*time.Second)
+	defer cancel()
+
+	userAgent := c.Request.UserAgent()
+	mutator := cfg.Rules.Mutator()
+
+	feedsIn := make([]*feedhlp.Feed, len(cfg.FeedURLs)+len(cfg.TG))
+	errs := make([]error, len(feedsIn))
+
+	g := new(errgroup.Group)
+
+	for i, el := range cfg.FeedURLs {
+		i, feedURL := i, el
+
+		g.Go(func() error {
+			feed, err := fetchFeed(ctx, feedURL, userAgent, opts)
+			if err != nil {
+				errs[i] = err
+
+				return nil
+			}
+
+			feedIn := feedhlp.MutateFeed(feed, keepItem)
+
+			processItems(feedIn, archiveKindFeed, feedURL, opts, cfg.History, mutator)
+			feedsIn[i] = feedIn
+
+			return nil
+		})
+	}
+
+	for i, el := range cfg.TG {
+		i, name := len(cfg.FeedURLs)+i, el
+
+		g.Go(func() error {
+			feed, err := fetchTGChannel(ctx, name, userAgent, opts)
+			if err != nil {
+				errs[i] = err
+
+				return nil
+			}
+
+			processItems(feed, archiveKindTG, name, opts, cfg.History, mutator)
+			feedsIn[i] = feed
+
+			return nil
+		})
+	}
+
+	_ = g.Wait()
+
+	var (
+		firstErr error
+		failed   int
+	)
+
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+
+		if firstErr == nil {
+			firstErr = err
+		}
+
+		failed++
+
+		// failed source is logged and skipped
+		_ = c.Error(err)
+	}
+
+	if failed == len(feedsIn) {
+		c.String(feedhlp.HTTPErrorResponse(firstErr))
+
+		return
+	}
+
+	feedOut := feedhlp.Merge(cfg.Title, feedsIn...)
+
+	serveFeed(c, feedOut, &cfg.FeedOptions, feedhlp.ContentTypeRSS, nil)
+}
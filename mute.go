@@ -7,30 +7,28 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/jlelse/feeds"
-	"github.com/mmcdole/gofeed"
-	"github.com/s3rj1k/yafp/pkg/cachedregexp"
 	"github.com/s3rj1k/yafp/pkg/feedhlp"
 	"github.com/s3rj1k/yafp/pkg/validation"
 )
 
-const (
-	feedFetchTimeoutSeconds = 90
-)
-
+// Mute requires at least one query or replace rule unless pass-through is requested,
+// see `ValidateMute`.
 type Mute struct {
-	FeedURL          string `form:"feed_url" binding:"required,url"`
-	TitleQuery       string `form:"title_query" binding:"required_without=DescriptionQuery,regexp"`
-	DescriptionQuery string `form:"description_query" binding:"required_without=TitleQuery,regexp"`
-	RewriteAuthor    string `form:"rewrite_author" binding:"ascii"`
+	FeedURL     string `form:"feed_url" json:"feedURL" binding:"required,url"`
+	FullText    bool   `form:"fulltext" json:"fullText"`
+	Upstream    string `form:"upstream" json:"upstream"`
+	Passthrough bool   `form:"passthrough" json:"passthrough"`
+
+	Rules
+	FeedOptions
 }
 
 func muteProperURLQueryParamsName() *strings.Replacer {
-	return strings.NewReplacer(
+	return properURLQueryParamsName(
 		"FeedURL", "feed_url",
-		"TitleQuery", "title_query",
-		"DescriptionQuery", "description_query",
-		"RewriteAuthor", "rewrite_author",
+		"FullText", "fulltext",
+		"Upstream", "upstream",
+		"Passthrough", "passthrough",
 	)
 }
 
@@ -45,64 +43,38 @@ func handleMuteFeed(c *gin.Context) {
 		return
 	}
 
-	reTitle := cachedregexp.MustCompile(cache, cfg.TitleQuery)
-	reDescription := cachedregexp.MustCompile(cache, cfg.DescriptionQuery)
-
-	fp := gofeed.NewParser()
+	serveMuteFeed(c, cfg)
+}
 
-	fp.UserAgent = c.Request.UserAgent()
+// serveMuteFeed fetches and mutates upstream feed, when output format
+// is neither set nor negotiated it is taken from upstream feed type.
+func serveMuteFeed(c *gin.Context, cfg *Mute) {
+	opts, ok := bindUpstream(c, cfg.Upstream)
+	if !ok {
+		return
+	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), feedFetchTimeoutSeconds*time.Second)
 	defer cancel()
 
-	feedIn, err := fp.ParseURLWithContext(cfg.FeedURL, ctx)
+	feedIn, err := fetchFeed(ctx, cfg.FeedURL, c.Request.UserAgent(), opts)
 	if err != nil {
 		c.String(feedhlp.HTTPErrorResponse(err))
 
 		return
 	}
 
-	if feedIn == nil {
-		c.String(http.StatusServiceUnavailable,
-			"%d Undefined input feed", http.StatusServiceUnavailable)
-
-		return
-	}
-
-	currentTime := time.Now()
+	feedOut := feedhlp.MutateFeed(feedIn, keepItem)
 
-	feedOut := feedhlp.MutateFeed(feedIn, func(item *feeds.Item) *feeds.Item {
-		if (cfg.TitleQuery != "" && reTitle.MatchString(item.Title)) ||
-			(cfg.DescriptionQuery != "" && reDescription.MatchString(item.Description)) {
-			if cfg.RewriteAuthor == "" {
-				// do not add item to resulting feed when
-				// RegExp matched and RewriteAuthor not specified
-				return nil
-			}
+	processItems(feedOut, archiveKindFeed, cfg.FeedURL, opts, cfg.History, cfg.Rules.Mutator())
 
-			item.Author = &feeds.Author{
-				Name:  cfg.RewriteAuthor,
-				Email: "",
-			}
+	var populate func(feed *feedhlp.Feed)
 
-			item.Updated = currentTime
+	if cfg.FullText {
+		populate = func(feed *feedhlp.Feed) {
+			populateFullText(ctx, feed, c.Request.UserAgent(), opts)
 		}
-
-		return item
-	})
-
-	contentType := feedhlp.GetContentTypeFromFeed(feedIn)
-	if contentType == "" {
-		contentType = feedhlp.ContentTypeRSS
-	}
-
-	out, err := feedhlp.RenderFeedBasedOnProvidedContentType(feedOut, contentType)
-	if err != nil {
-		c.String(http.StatusServiceUnavailable,
-			"%d Unable to build feed", http.StatusServiceUnavailable)
-
-		return
 	}
 
-	c.Data(http.StatusOK, contentType, []byte(out))
+	serveFeed(c, feedOut, &cfg.FeedOptions, feedhlp.GetContentTypeFromFeed(feedIn), populate)
 }
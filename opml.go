@@ -0,0 +1,154 @@
+package main
+
+import (
+	"net/http"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/s3rj1k/yafp/pkg/opml"
+	"github.com/s3rj1k/yafp/pkg/validation"
+)
+
+const opmlTitle = "yafp"
+
+// OPMLImport holds rules and options that are added to every imported feed.
+type OPMLImport struct {
+	Upstream string `form:"upstream"`
+
+	Rules
+	FeedOptions
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	out := make([]string, 0, len(m))
+
+	for key := range m {
+		out = append(out, key)
+	}
+
+	sort.Strings(out)
+
+	return out
+}
+
+func opmlFolder(title string, names []string, base, path string, htmlURL func(name string) string) *opml.Outline {
+	folder := &opml.Outline{
+		Text:     title,
+		Title:    title,
+		Outlines: make([]*opml.Outline, 0, len(names)),
+	}
+
+	for _, name := range names {
+		folder.Outlines = append(folder.Outlines, &opml.Outline{
+			Text:    name,
+			Title:   name,
+			Type:    opml.OutlineTypeRSS,
+			XMLURL:  base + path + url.PathEscape(name),
+			HTMLURL: htmlURL(name),
+		})
+	}
+
+	return folder
+}
+
+// handleOPMLExport lists named feeds from configuration grouped by source type.
+func handleOPMLExport(c *gin.Context) {
+	base := publicBaseURL(c)
+	doc := opml.New(opmlTitle)
+
+	doc.Head.DateCreated = time.Now().UTC().Format(time.RFC1123Z)
+
+	if len(config.Profiles) > 0 {
+		doc.Body.Outlines = append(doc.Body.Outlines,
+			opmlFolder("Profiles", sortedKeys(config.Profiles), base, "/p/", func(name string) string {
+				return config.Profiles[name].FeedURL
+			}),
+		)
+	}
+
+	if len(config.Scrapers) > 0 {
+		doc.Body.Outlines = append(doc.Body.Outlines,
+			opmlFolder("Scrapers", sortedKeys(config.Scrapers), base, "/scrape/", func(name string) string {
+				return config.Scrapers[name].URL
+			}),
+		)
+	}
+
+	if len(config.JSON) > 0 {
+		doc.Body.Outlines = append(doc.Body.Outlines,
+			opmlFolder("JSON", sortedKeys(config.JSON), base, "/json/", func(name string) string {
+				return config.JSON[name].URL
+			}),
+		)
+	}
+
+	out, err := doc.Render()
+	if err != nil {
+		c.String(http.StatusServiceUnavailable,
+			"%d Unable to build OPML", http.StatusServiceUnavailable)
+
+		return
+	}
+
+	c.Data(http.StatusOK, opml.ContentType, []byte(out))
+}
+
+// handleOPMLImport wraps every feed of uploaded OPML with `/mute` URL, query parameters
+// are added to every wrapped URL, feeds are passed through when no rule is set.
+func handleOPMLImport(c *gin.Context) {
+	cfg := new(OPMLImport)
+
+	if err := c.BindQuery(cfg); err != nil {
+		c.String(http.StatusBadRequest, "%s\n",
+			validation.ErrorResponse(err, properURLQueryParamsName(
+				"Upstream", "upstream",
+			)),
+		)
+
+		return
+	}
+
+	if _, ok := bindUpstream(c, cfg.Upstream); !ok {
+		return
+	}
+
+	doc, err := opml.Parse(http.MaxBytesReader(c.Writer, c.Request.Body, maxDocumentSizeBytes))
+	if err != nil {
+		c.String(http.StatusBadRequest, "%d Invalid OPML\n", http.StatusBadRequest)
+
+		return
+	}
+
+	endpoint := publicBaseURL(c) + "/mute"
+
+	params := c.Request.URL.Query()
+
+	if !cfg.Rules.hasQuery() && !cfg.Rules.hasReplace() {
+		params.Set("passthrough", "true")
+	}
+
+	doc.Walk(func(el *opml.Outline) {
+		query := make(url.Values, len(params)+1)
+
+		for key, val := range params {
+			query[key] = val
+		}
+
+		query.Set("feed_url", el.XMLURL)
+
+		el.Type = opml.OutlineTypeRSS
+		el.XMLURL = endpoint + "?" + query.Encode()
+	})
+
+	out, err := doc.Render()
+	if err != nil {
+		c.String(http.StatusServiceUnavailable,
+			"%d Unable to build OPML", http.StatusServiceUnavailable)
+
+		return
+	}
+
+	c.Data(http.StatusOK, opml.ContentType, []byte(out))
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"strings"
+	"time"
+
+	"github.com/araddon/dateparse"
+	"github.com/s3rj1k/yafp/pkg/feedhlp"
+	"github.com/s3rj1k/yafp/pkg/imgproxy"
+	"github.com/s3rj1k/yafp/pkg/sanitize"
+)
+
+const (
+	undatedDrop = "drop"
+
+	dedupeKeepOldest = "oldest"
+)
+
+// FeedOptions are applied to resulting feed on every route.
+type FeedOptions struct {
+	MaxAge  string `form:"max_age" json:"maxAge" binding:"omitempty,duration"`
+	Since   string `form:"since" json:"since" binding:"omitempty,date"`
+	Until   string `form:"until" json:"until" binding:"omitempty,date"`
+	Undated string `form:"undated" json:"undated" binding:"omitempty,oneof=keep drop"`
+
+	Dedupe     string `form:"dedupe" json:"dedupe" binding:"omitempty,oneof=title link guid"`
+	DedupeKeep string `form:"dedupe_keep" json:"dedupeKeep" binding:"omitempty,oneof=newest oldest"`
+
+	Sort   string `form:"sort" json:"sort" binding:"omitempty,oneof=date -date title -title"`
+	Offset int    `form:"offset" json:"offset" binding:"min=0"`
+	Limit  int    `form:"limit" json:"limit" binding:"min=0"`
+
+	Format string `form:"format" json:"format" binding:"omitempty,oneof=rss atom json"`
+
+	// number of items served from archive of source, used when archive is enabled
+	History int `form:"history" json:"history" binding:"min=0,max=1000"`
+
+	// nil means enabled, HTML is sanitized unless explicitly disabled
+	Sanitize    *bool `form:"sanitize" json:"sanitize"`
+	ProxyImages bool  `form:"proxy_images" json:"proxyImages"`
+}
+
+func feedOptionsProperURLQueryParamsName() []string {
+	return []string{
+		"MaxAge", "max_age",
+		"Since", "since",
+		"Until", "until",
+		"Undated", "undated",
+		"DedupeKeep", "dedupe_keep",
+		"Dedupe", "dedupe",
+		"Sort", "sort",
+		"Offset", "offset",
+		"Limit", "limit",
+		"Format", "format",
+		"History", "history",
+		"Sanitize", "sanitize",
+		"ProxyImages", "proxy_images",
+	}
+}
+
+// properURLQueryParamsName returns replacer of struct field names with URL query parameter names
+// for route with rules and feed options. Replacer matches names in arguments order, route
+// specific names (e.g. `Title`) go after rules names that start with them (e.g. `TitleQuery`).
+func properURLQueryParamsName(routeNames ...string) *strings.Replacer {
+	return strings.NewReplacer(append(append(rulesProperURLQueryParamsName(),
+		feedOptionsProperURLQueryParamsName()...),
+		routeNames...,
+	)...)
+}
+
+// ContentType selects output content type, explicit format takes precedence
+// over `Accept` header negotiation, fallback is used when nothing is selected.
+func (o *FeedOptions) ContentType(accept, fallback string) string {
+	if contentType := feedhlp.GetContentTypeFromFormat(o.Format); contentType != "" {
+		return contentType
+	}
+
+	if contentType := feedhlp.NegotiateContentType(accept); contentType != "" {
+		return contentType
+	}
+
+	if fallback != "" {
+		return fallback
+	}
+
+	return feedhlp.ContentTypeRSS
+}
+
+// Apply post-processes feed, options are expected to be validated.
+func (o *FeedOptions) Apply(feed *feedhlp.Feed) {
+	var since, until time.Time
+
+	if o.MaxAge != "" {
+		if val, err := time.ParseDuration(o.MaxAge); err == nil {
+			since = time.Now().Add(-val)
+		}
+	}
+
+	if o.Since != "" {
+		if val, err := dateparse.ParseAny(o.Since); err == nil && val.After(since) {
+			since = val
+		}
+	}
+
+	if o.Until != "" {
+		if val, err := dateparse.ParseAny(o.Until); err == nil {
+			until = val
+		}
+	}
+
+	if !since.IsZero() || !until.IsZero() || o.Undated == undatedDrop {
+		feedhlp.FilterByDate(feed, since, until, o.Undated != undatedDrop)
+	}
+
+	if o.Dedupe != "" {
+		feedhlp.Dedupe(feed, o.Dedupe, o.DedupeKeep != dedupeKeepOldest)
+	}
+
+	if o.Sort != "" {
+		feedhlp.SortItems(feed, o.Sort)
+	}
+
+	if o.Offset > 0 || o.Limit > 0 {
+		feedhlp.SliceItems(feed, o.Offset, o.Limit)
+	}
+}
+
+// Finalize post-processes feed content, it runs after items content is complete,
+// proxyImage rewrites image URL to image proxy URL.
+func (o *FeedOptions) Finalize(feed *feedhlp.Feed, proxyImage func(src string) string) {
+	if o.Sanitize == nil || *o.Sanitize {
+		feedhlp.MapHTML(feed, sanitize.HTML)
+	}
+
+	if o.ProxyImages {
+		feedhlp.MapHTML(feed, func(in string) string {
+			return imgproxy.RewriteHTML(in, proxyImage)
+		})
+
+		feedhlp.MapImages(feed, proxyImage)
+	}
+}
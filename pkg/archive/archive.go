@@ -0,0 +1,336 @@
+package archive
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/s3rj1k/yafp/pkg/feedhlp"
+)
+
+const (
+	fileExtension = ".json"
+	filePerm      = 0o600
+	dirPerm       = 0o700
+)
+
+// entry is archived item with time it was first seen,
+// seen time orders items that have no publication date.
+type entry struct {
+	Seen time.Time     `json:"seen"`
+	Item *feedhlp.Item `json:"item"`
+}
+
+// sourceLock serializes merges of one source, it is dropped when no merge holds it.
+type sourceLock struct {
+	mu   sync.Mutex
+	refs int
+}
+
+// Archive keeps items seen per source on disk, every source is stored in own JSON file
+// named by hash of source key, files are replaced atomically. File modification time
+// is time of the last merge, sources are evicted by it.
+type Archive struct {
+	dir        string
+	maxItems   int
+	maxSources int
+	maxIdle    time.Duration
+
+	// mu guards source locks and eviction
+	mu    sync.Mutex
+	locks map[string]*sourceLock
+}
+
+// New creates archive in provided directory (directory is created when missing),
+// only newest `maxItems` items are kept per source. Least recently merged sources
+// over `maxSources` and sources not merged for `maxIdle` are removed, zero means no limit.
+func New(dir string, maxItems, maxSources int, maxIdle time.Duration) (*Archive, error) {
+	if err := os.MkdirAll(dir, dirPerm); err != nil {
+		return nil, fmt.Errorf("create archive directory error: %w", err)
+	}
+
+	return &Archive{
+		dir:        dir,
+		maxItems:   maxItems,
+		maxSources: maxSources,
+		maxIdle:    maxIdle,
+		locks:      make(map[string]*sourceLock),
+	}, nil
+}
+
+// lock locks source and returns function that unlocks it.
+func (a *Archive) lock(source string) func() {
+	a.mu.Lock()
+
+	l, ok := a.locks[source]
+	if !ok {
+		l = new(sourceLock)
+		a.locks[source] = l
+	}
+
+	l.refs++
+
+	a.mu.Unlock()
+
+	l.mu.Lock()
+
+	return func() {
+		l.mu.Unlock()
+
+		a.mu.Lock()
+		defer a.mu.Unlock()
+
+		l.refs--
+
+		if l.refs == 0 {
+			delete(a.locks, source)
+		}
+	}
+}
+
+// Prune removes sources that were not merged for `maxIdle`.
+func (a *Archive) Prune() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.evict(0)
+}
+
+// evict removes idle sources and least recently merged sources, so that at most
+// `maxSources` minus `reserve` sources are left, it must be called with archive lock held.
+func (a *Archive) evict(reserve int) error {
+	dirEntries, err := os.ReadDir(a.dir)
+	if err != nil {
+		return fmt.Errorf("read archive directory error: %w", err)
+	}
+
+	type sourceFile struct {
+		name    string
+		modTime time.Time
+	}
+
+	sources := make([]sourceFile, 0, len(dirEntries))
+
+	for _, el := range dirEntries {
+		if el.IsDir() || filepath.Ext(el.Name()) != fileExtension {
+			continue
+		}
+
+		info, infoErr := el.Info()
+		if infoErr != nil {
+			continue // removed meanwhile
+		}
+
+		sources = append(sources, sourceFile{
+			name:    el.Name(),
+			modTime: info.ModTime(),
+		})
+	}
+
+	// most recently merged sources go first
+	sort.Slice(sources, func(i, j int) bool {
+		return sources[i].modTime.After(sources[j].modTime)
+	})
+
+	keep := len(sources)
+
+	if a.maxSources > 0 && keep > a.maxSources-reserve {
+		keep = a.maxSources - reserve
+	}
+
+	if keep < 0 {
+		keep = 0
+	}
+
+	for i, el := range sources {
+		if i < keep && (a.maxIdle <= 0 || time.Since(el.modTime) <= a.maxIdle) {
+			continue
+		}
+
+		if err = os.Remove(filepath.Join(a.dir, el.name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("remove archive error: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// itemKey identifies item, GUID is preferred, then link and title.
+func itemKey(item *feedhlp.Item) string {
+	for _, by := range []string{feedhlp.DedupeByGUID, feedhlp.DedupeByLink, feedhlp.DedupeByTitle} {
+		if key := feedhlp.DedupeKey(item, by); key != "" {
+			return by + ":" + key
+		}
+	}
+
+	return ""
+}
+
+func entryDate(el *entry) time.Time {
+	if date := feedhlp.ItemDate(el.Item); !date.IsZero() {
+		return date
+	}
+
+	return el.Seen
+}
+
+func (a *Archive) path(source string) string {
+	sum := sha256.Sum256([]byte(source))
+
+	return filepath.Join(a.dir, hex.EncodeToString(sum[:])+fileExtension)
+}
+
+func (a *Archive) load(source string) ([]*entry, error) {
+	data, err := os.ReadFile(a.path(source))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("read archive error: %w", err)
+	}
+
+	var entries []*entry
+
+	if err = json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("decode archive error: %w", err)
+	}
+
+	return entries, nil
+}
+
+func (a *Archive) store(source string, entries []*entry) error {
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return fmt.Errorf("encode archive error: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(a.dir, ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("create archive error: %w", err)
+	}
+
+	defer os.Remove(tmp.Name())
+
+	if _, err = tmp.Write(data); err != nil {
+		_ = tmp.Close()
+
+		return fmt.Errorf("write archive error: %w", err)
+	}
+
+	if err = tmp.Chmod(filePerm); err != nil {
+		_ = tmp.Close()
+
+		return fmt.Errorf("write archive error: %w", err)
+	}
+
+	if err = tmp.Close(); err != nil {
+		return fmt.Errorf("write archive error: %w", err)
+	}
+
+	if err = os.Rename(tmp.Name(), a.path(source)); err != nil {
+		return fmt.Errorf("replace archive error: %w", err)
+	}
+
+	return nil
+}
+
+// Merge records feed items of source (known items are updated) and appends
+// newest archived items missing from feed until feed has `depth` items,
+// zero depth only records items. Items without GUID, link and title are not archived.
+// New source evicts least recently merged source when archive is full.
+func (a *Archive) Merge(source string, feed *feedhlp.Feed, depth int) error {
+	if feed == nil {
+		return feedhlp.ErrUndefinedFeed
+	}
+
+	unlock := a.lock(source)
+	defer unlock()
+
+	entries, err := a.load(source)
+	if err != nil {
+		return err
+	}
+
+	if entries == nil {
+		a.mu.Lock()
+		err = a.evict(1)
+		a.mu.Unlock()
+
+		if err != nil {
+			return err
+		}
+	}
+
+	index := make(map[string]*entry, len(entries))
+
+	for _, el := range entries {
+		if el == nil || el.Item == nil {
+			continue
+		}
+
+		index[itemKey(el.Item)] = el
+	}
+
+	now := time.Now()
+	fresh := make(map[string]struct{}, len(feed.Items))
+
+	for _, item := range feed.Items {
+		key := itemKey(item)
+		if key == "" {
+			continue
+		}
+
+		fresh[key] = struct{}{}
+
+		if el, ok := index[key]; ok {
+			el.Item = item
+
+			continue
+		}
+
+		index[key] = &entry{
+			Seen: now,
+			Item: item,
+		}
+	}
+
+	entries = make([]*entry, 0, len(index))
+
+	for _, el := range index {
+		entries = append(entries, el)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entryDate(entries[i]).After(entryDate(entries[j]))
+	})
+
+	if a.maxItems > 0 && len(entries) > a.maxItems {
+		entries = entries[:a.maxItems]
+	}
+
+	if err = a.store(source, entries); err != nil {
+		return err
+	}
+
+	for _, el := range entries {
+		if len(feed.Items) >= depth {
+			break
+		}
+
+		if _, ok := fresh[itemKey(el.Item)]; ok {
+			continue
+		}
+
+		feed.Items = append(feed.Items, el.Item)
+	}
+
+	return nil
+}
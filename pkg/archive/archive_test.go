@@ -0,0 +1,171 @@
+package archive_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jlelse/feeds"
+	"github.com/s3rj1k/yafp/pkg/archive"
+	"github.com/s3rj1k/yafp/pkg/feedhlp"
+	"github.com/stretchr/testify/assert"
+)
+
+func newFeed(now time.Time, ids ...string) *feedhlp.Feed {
+	feed := new(feedhlp.Feed)
+
+	for i, id := range ids {
+		item := new(feedhlp.Item)
+
+		item.Id = id
+		item.Title = "title " + id
+		item.Link = &feeds.Link{Href: "https://example.com/" + id}
+		item.Created = now.Add(-time.Duration(i) * time.Hour)
+
+		feed.Items = append(feed.Items, item)
+	}
+
+	return feed
+}
+
+func ids(feed *feedhlp.Feed) []string {
+	out := make([]string, 0, len(feed.Items))
+
+	for _, el := range feed.Items {
+		out = append(out, el.Id)
+	}
+
+	return out
+}
+
+func TestMerge(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	a, err := archive.New(dir, 4, 0, 0)
+	assert.NoError(t, err)
+
+	now := time.Now()
+
+	feed := newFeed(now.Add(-3*time.Hour), "c", "b", "a")
+	assert.NoError(t, a.Merge("src", feed, 0))
+	assert.Equal(t, []string{"c", "b", "a"}, ids(feed))
+
+	// upstream window moved, archived items are appended newest first,
+	// only `maxItems` newest items are kept
+	feed = newFeed(now, "e", "d", "c")
+	feed.Items[2].Title = "updated"
+	assert.NoError(t, a.Merge("src", feed, 5))
+	assert.Equal(t, []string{"e", "d", "c", "b"}, ids(feed))
+
+	// known items are updated
+	feed = newFeed(now, "e")
+	assert.NoError(t, a.Merge("src", feed, 10))
+	assert.Equal(t, []string{"e", "d", "c", "b"}, ids(feed))
+	assert.Equal(t, "updated", feed.Items[2].Title)
+
+	// depth limits number of items
+	feed = newFeed(now, "e")
+	assert.NoError(t, a.Merge("src", feed, 2))
+	assert.Equal(t, []string{"e", "d"}, ids(feed))
+
+	// sources are independent
+	feed = newFeed(now, "x")
+	assert.NoError(t, a.Merge("other", feed, 10))
+	assert.Equal(t, []string{"x"}, ids(feed))
+
+	// archive survives restart
+	a, err = archive.New(dir, 4, 0, 0)
+	assert.NoError(t, err)
+
+	feed = newFeed(now, "e")
+	assert.NoError(t, a.Merge("src", feed, 3))
+	assert.Equal(t, []string{"e", "d", "c"}, ids(feed))
+
+	assert.Error(t, a.Merge("src", nil, 1))
+}
+
+func TestEvict(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	a, err := archive.New(dir, 4, 2, time.Hour)
+	assert.NoError(t, err)
+
+	now := time.Now()
+
+	sources := func() int {
+		entries, err := os.ReadDir(dir)
+		assert.NoError(t, err)
+
+		return len(entries)
+	}
+
+	for _, source := range []string{"a", "b", "c"} {
+		assert.NoError(t, a.Merge(source, newFeed(now, source+"1"), 0))
+
+		// sources are ordered by file modification time
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// least recently merged source is evicted
+	assert.Equal(t, 2, sources())
+
+	feed := newFeed(now, "a2")
+	assert.NoError(t, a.Merge("a", feed, 10))
+	assert.Equal(t, []string{"a2"}, ids(feed))
+
+	// recently merged source is kept
+	feed = newFeed(now, "c2")
+	assert.NoError(t, a.Merge("c", feed, 10))
+	assert.Equal(t, []string{"c2", "c1"}, ids(feed))
+
+	// idle sources are pruned
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+
+	for _, el := range entries {
+		old := now.Add(-2 * time.Hour)
+		assert.NoError(t, os.Chtimes(filepath.Join(dir, el.Name()), old, old))
+	}
+
+	assert.NoError(t, a.Prune())
+	assert.Equal(t, 0, sources())
+}
+
+func TestMergeConcurrent(t *testing.T) {
+	t.Parallel()
+
+	a, err := archive.New(t.TempDir(), 100, 0, 0)
+	assert.NoError(t, err)
+
+	now := time.Now()
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			source := fmt.Sprintf("src%d", i%4)
+
+			assert.NoError(t, a.Merge(source, newFeed(now, fmt.Sprintf("%s-%d", source, i)), 0))
+		}(i)
+	}
+
+	wg.Wait()
+
+	// every merge of source is recorded
+	for i := 0; i < 4; i++ {
+		feed := newFeed(now)
+		assert.NoError(t, a.Merge(fmt.Sprintf("src%d", i), feed, 100))
+		assert.Len(t, feed.Items, 5)
+	}
+}
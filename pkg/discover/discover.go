@@ -0,0 +1,117 @@
+package discover
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+const (
+	TypeRSS  = "rss"
+	TypeAtom = "atom"
+	TypeJSON = "json"
+)
+
+// link types of feed alternates, lower rank is preferred
+//
+//nolint:gochecknoglobals // read-only lookup table
+var feedTypes = map[string]struct {
+	name string
+	rank int
+}{
+	"application/rss+xml":   {TypeRSS, 0},
+	"application/atom+xml":  {TypeAtom, 0},
+	"application/rdf+xml":   {TypeRSS, 1},
+	"application/feed+json": {TypeJSON, 1},
+	"application/json":      {TypeJSON, 2},
+}
+
+type Candidate struct {
+	URL   string `json:"url"`
+	Title string `json:"title,omitempty"`
+	Type  string `json:"type"`
+
+	rank int
+}
+
+func isCommentsFeed(href, title string) bool {
+	return strings.Contains(strings.ToLower(title), "comments") ||
+		strings.Contains(strings.ToLower(href), "/comments/")
+}
+
+// Find returns feed candidates advertised by `<link rel="alternate">` elements,
+// relative links are resolved against base URL (when defined). Candidates are
+// ordered from the best one: RSS and Atom first, comments feeds last.
+func Find(doc *goquery.Document, base *url.URL) []Candidate {
+	out := make([]Candidate, 0)
+	seen := make(map[string]bool)
+
+	doc.Find("link[href]").Each(func(_ int, selection *goquery.Selection) {
+		rel, _ := selection.Attr("rel")
+		if !containsToken(rel, "alternate") {
+			return
+		}
+
+		typ, _ := selection.Attr("type")
+
+		feedType, ok := feedTypes[strings.ToLower(strings.TrimSpace(typ))]
+		if !ok {
+			return
+		}
+
+		href, _ := selection.Attr("href")
+
+		href = strings.TrimSpace(href)
+		if href == "" {
+			return
+		}
+
+		if base != nil {
+			u, err := base.Parse(href)
+			if err != nil {
+				return
+			}
+
+			href = u.String()
+		}
+
+		if seen[href] {
+			return
+		}
+
+		seen[href] = true
+
+		title, _ := selection.Attr("title")
+
+		candidate := Candidate{
+			URL:   href,
+			Title: strings.TrimSpace(title),
+			Type:  feedType.name,
+			rank:  feedType.rank,
+		}
+
+		if isCommentsFeed(candidate.URL, candidate.Title) {
+			candidate.rank += len(feedTypes)
+		}
+
+		out = append(out, candidate)
+	})
+
+	sort.SliceStable(out, func(i, j int) bool {
+		return out[i].rank < out[j].rank
+	})
+
+	return out
+}
+
+func containsToken(val, token string) bool {
+	for _, el := range strings.Fields(val) {
+		if strings.EqualFold(el, token) {
+			return true
+		}
+	}
+
+	return false
+}
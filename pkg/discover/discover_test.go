@@ -0,0 +1,41 @@
+package discover_test
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/s3rj1k/yafp/pkg/discover"
+	"github.com/stretchr/testify/assert"
+)
+
+const page = `<html><head>
+<link rel="stylesheet" href="/style.css">
+<link rel="alternate" type="application/rss+xml" title="Comments Feed" href="/comments/feed/">
+<link rel="alternate" type="application/feed+json" title="JSON" href="/feed.json">
+<link rel="alternate" type="application/atom+xml" title="Atom" href="https://example.com/atom.xml">
+<link rel="alternate" type="text/html" hreflang="en" href="/en/">
+<link rel="alternate" type="application/atom+xml" href="/atom.xml">
+</head><body></body></html>`
+
+func TestFind(t *testing.T) {
+	t.Parallel()
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(page))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	base, _ := url.Parse("https://example.com/blog/")
+
+	out := discover.Find(doc, base)
+	if !assert.Len(t, out, 3) {
+		return
+	}
+
+	assert.Equal(t, "https://example.com/atom.xml", out[0].URL)
+	assert.Equal(t, discover.TypeAtom, out[0].Type)
+	assert.Equal(t, "https://example.com/feed.json", out[1].URL)
+	assert.Equal(t, "https://example.com/comments/feed/", out[2].URL)
+}
@@ -3,8 +3,10 @@ package feedhlp
 import (
 	"errors"
 	"io"
+	"mime"
+	"strconv"
+	"strings"
 
-	"github.com/jlelse/feeds"
 	"github.com/mmcdole/gofeed"
 )
 
@@ -14,6 +16,12 @@ const (
 	ContentTypeJSON = "application/feed+json"
 )
 
+const (
+	FormatRSS  = "rss"
+	FormatAtom = "atom"
+	FormatJSON = "json"
+)
+
 var ErrUnknownContentType = errors.New("unknown Content-Type")
 
 func GetContentTypeFromReader(feed io.Reader) string {
@@ -50,7 +58,63 @@ func GetContentTypeFromFeed(feed *gofeed.Feed) string {
 	return contentType
 }
 
-func RenderFeedBasedOnProvidedContentType(feed *feeds.Feed, contentType string) (string, error) {
+func GetContentTypeFromFormat(format string) string {
+	switch format {
+	case FormatRSS:
+		return ContentTypeRSS
+	case FormatAtom:
+		return ContentTypeAtom
+	case FormatJSON:
+		return ContentTypeJSON
+	}
+
+	return ""
+}
+
+// NegotiateContentType selects feed content type from `Accept` header value,
+// returns empty string when none of feed types is preferred over others.
+func NegotiateContentType(accept string) string {
+	var (
+		contentType string
+		quality     float64
+	)
+
+	for _, el := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(el))
+		if err != nil {
+			continue
+		}
+
+		q := 1.0
+
+		if val, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(val, 64); err != nil {
+				continue
+			}
+		}
+
+		var candidate string
+
+		switch mediaType {
+		case ContentTypeRSS:
+			candidate = ContentTypeRSS
+		case ContentTypeAtom:
+			candidate = ContentTypeAtom
+		case ContentTypeJSON, "application/json":
+			candidate = ContentTypeJSON
+		default:
+			continue
+		}
+
+		if q > quality {
+			contentType, quality = candidate, q
+		}
+	}
+
+	return contentType
+}
+
+func RenderFeedBasedOnProvidedContentType(feed *Feed, contentType string) (string, error) {
 	var (
 		out string
 		err error
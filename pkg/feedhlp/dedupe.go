@@ -0,0 +1,118 @@
+package feedhlp
+
+import (
+	"net/url"
+	"strings"
+	"unicode"
+)
+
+const (
+	DedupeByTitle = "title"
+	DedupeByLink  = "link"
+	DedupeByGUID  = "guid"
+)
+
+//nolint:gochecknoglobals // list of known tracking URL query parameters
+var trackingQueryParams = map[string]struct{}{
+	"fbclid":  {},
+	"gclid":   {},
+	"yclid":   {},
+	"dclid":   {},
+	"msclkid": {},
+	"mc_cid":  {},
+	"mc_eid":  {},
+	"igshid":  {},
+	"_hsenc":  {},
+	"_hsmi":   {},
+}
+
+func normalizeText(s string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsPunct(r) || unicode.IsSymbol(r)
+	}), " ")
+}
+
+func normalizeLink(s string) string {
+	u, err := url.Parse(strings.TrimSpace(s))
+	if err != nil || u.Host == "" {
+		return strings.TrimSpace(s)
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.TrimPrefix(strings.ToLower(u.Host), "www.")
+	u.Path = strings.TrimRight(u.Path, "/")
+	u.Fragment = ""
+	u.RawFragment = ""
+
+	query := u.Query()
+
+	for key := range query {
+		if _, ok := trackingQueryParams[strings.ToLower(key)]; ok ||
+			strings.HasPrefix(strings.ToLower(key), "utm_") {
+			query.Del(key)
+		}
+	}
+
+	u.RawQuery = query.Encode() // sorted by key
+
+	if u.Scheme == "http" {
+		u.Scheme = "https"
+	}
+
+	return u.String()
+}
+
+// DedupeKey returns normalized item key, empty key means item can not be deduplicated.
+func DedupeKey(item *Item, by string) string {
+	switch by {
+	case DedupeByTitle:
+		return normalizeText(item.Title)
+	case DedupeByLink:
+		if item.Link == nil {
+			return ""
+		}
+
+		return normalizeLink(item.Link.Href)
+	case DedupeByGUID:
+		return strings.ToLower(strings.TrimSpace(item.Id))
+	}
+
+	return ""
+}
+
+// Dedupe keeps single (newest or oldest) item for every key, items order is preserved.
+func Dedupe(feed *Feed, by string, keepNewest bool) {
+	selected := make(map[string]*Item, len(feed.Items))
+
+	for _, el := range feed.Items {
+		key := DedupeKey(el, by)
+		if key == "" {
+			continue
+		}
+
+		prev, ok := selected[key]
+		if !ok {
+			selected[key] = el
+
+			continue
+		}
+
+		if keepNewest && ItemDate(el).After(ItemDate(prev)) ||
+			!keepNewest && ItemDate(el).Before(ItemDate(prev)) {
+			selected[key] = el
+		}
+	}
+
+	items := make([]*Item, 0, len(selected))
+
+	for _, el := range feed.Items {
+		key := DedupeKey(el, by)
+		if key != "" && selected[key] != el {
+			continue
+		}
+
+		items = append(items, el)
+	}
+
+	feed.Items = items
+}
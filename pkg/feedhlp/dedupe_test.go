@@ -0,0 +1,64 @@
+package feedhlp_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jlelse/feeds"
+	"github.com/s3rj1k/yafp/pkg/feedhlp"
+	"github.com/stretchr/testify/assert"
+)
+
+func newItem(id, title, link string, created time.Time) *feedhlp.Item {
+	item := new(feedhlp.Item)
+
+	item.Id = id
+	item.Title = title
+	item.Link = &feeds.Link{Href: link}
+	item.Created = created
+
+	return item
+}
+
+func ids(feed *feedhlp.Feed) []string {
+	out := make([]string, 0, len(feed.Items))
+
+	for _, el := range feed.Items {
+		out = append(out, el.Id)
+	}
+
+	return out
+}
+
+func TestDedupe(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+
+	newFeed := func() *feedhlp.Feed {
+		return &feedhlp.Feed{
+			Items: []*feedhlp.Item{
+				newItem("1", "Some Release (2022)", "http://www.example.com/a/?utm_source=rss#top", now.Add(-2*time.Hour)),
+				newItem("2", "some  release 2022!", "https://example.com/a?fbclid=x", now),
+				newItem("3", "Other", "https://example.com/b?id=1", now.Add(-time.Hour)),
+				newItem("1", "", "https://example.com/b?id=2", now.Add(-3*time.Hour)),
+			},
+		}
+	}
+
+	feed := newFeed()
+	feedhlp.Dedupe(feed, feedhlp.DedupeByTitle, true)
+	assert.Equal(t, []string{"2", "3", "1"}, ids(feed))
+
+	feed = newFeed()
+	feedhlp.Dedupe(feed, feedhlp.DedupeByTitle, false)
+	assert.Equal(t, []string{"1", "3", "1"}, ids(feed))
+
+	feed = newFeed()
+	feedhlp.Dedupe(feed, feedhlp.DedupeByLink, true)
+	assert.Equal(t, []string{"2", "3", "1"}, ids(feed))
+
+	feed = newFeed()
+	feedhlp.Dedupe(feed, feedhlp.DedupeByGUID, true)
+	assert.Equal(t, []string{"1", "2", "3"}, ids(feed))
+}
@@ -9,6 +9,8 @@ import (
 	"github.com/mmcdole/gofeed"
 )
 
+var ErrUndefinedFeed = errors.New("undefined input feed")
+
 func HTTPErrorResponse(err error) (statusCode int, status string) {
 	if err == nil {
 		return http.StatusOK, fmt.Sprintf("%d OK", http.StatusOK)
@@ -26,6 +28,10 @@ func HTTPErrorResponse(err error) (statusCode int, status string) {
 		return http.StatusServiceUnavailable, fmt.Sprintf("%d Failed to detect feed type", http.StatusServiceUnavailable)
 	}
 
+	if errors.Is(err, ErrUndefinedFeed) {
+		return http.StatusServiceUnavailable, fmt.Sprintf("%d Undefined input feed", http.StatusServiceUnavailable)
+	}
+
 	var httpError gofeed.HTTPError
 
 	if errors.As(err, &httpError) {
@@ -0,0 +1,25 @@
+package feedhlp
+
+// MapHTML replaces HTML fields (feed description, items description
+// and content) with result of provided function.
+func MapHTML(feed *Feed, fn func(string) string) {
+	feed.Description = fn(feed.Description)
+
+	for _, el := range feed.Items {
+		el.Description = fn(el.Description)
+		el.Content = fn(el.Content)
+	}
+}
+
+// MapImages replaces feed and items image URLs with result of provided function.
+func MapImages(feed *Feed, fn func(string) string) {
+	if feed.Image != nil {
+		feed.Image.Url = fn(feed.Image.Url)
+	}
+
+	for _, el := range feed.Items {
+		if el.Image != nil {
+			el.Image.Url = fn(el.Image.Url)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package feedhlp
+
+import (
+	"github.com/jlelse/feeds"
+)
+
+// Feed extends feeds.Feed with items that carry data not modelled by jlelse/feeds,
+// embedded feeds.Feed.Items is not used, feed items are stored in Items.
+type Feed struct {
+	feeds.Feed
+
+	Items []*Item
+}
+
+// Item extends feeds.Item with upstream data that jlelse/feeds drops on render.
+type Item struct {
+	feeds.Item
+
+	Image      *feeds.Image
+	Comments   string
+	Categories []string
+	Enclosures []*feeds.Enclosure
+}
+
+// toFeeds returns copy of embedded feeds.Feed populated with items.
+func (f *Feed) toFeeds() *feeds.Feed {
+	out := f.Feed
+
+	if out.Link == nil {
+		out.Link = new(feeds.Link)
+	}
+
+	out.Items = make([]*feeds.Item, 0, len(f.Items))
+
+	for _, el := range f.Items {
+		item := el.Item
+
+		if item.Link == nil {
+			item.Link = new(feeds.Link)
+		}
+
+		out.Items = append(out.Items, &item)
+	}
+
+	return &out
+}
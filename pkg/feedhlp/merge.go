@@ -0,0 +1,61 @@
+package feedhlp
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jlelse/feeds"
+)
+
+// Merge combines items of provided feeds into new feed sorted by date (newest first),
+// item with already seen ID gets its link (or source feed link) as ID.
+func Merge(title string, in ...*Feed) *Feed {
+	feedOut := new(Feed)
+
+	feedOut.Title = title
+	feedOut.Link = new(feeds.Link)
+	feedOut.Updated = time.Now()
+
+	titles := make([]string, 0, len(in))
+	seen := make(map[string]struct{})
+
+	for _, feed := range in {
+		if feed == nil {
+			continue
+		}
+
+		titles = append(titles, feed.Title)
+
+		for _, el := range feed.Items {
+			if el == nil {
+				continue
+			}
+
+			if _, ok := seen[el.Id]; ok || el.Id == "" {
+				switch {
+				case el.Link != nil && el.Link.Href != "":
+					el.Id = el.Link.Href
+				case feed.Link != nil:
+					el.Id = feed.Link.Href + "#" + el.Id
+				}
+			}
+
+			seen[el.Id] = struct{}{}
+
+			feedOut.Items = append(feedOut.Items, el)
+		}
+	}
+
+	if feedOut.Title == "" {
+		feedOut.Title = strings.Join(titles, " | ")
+	}
+
+	feedOut.Description = strings.Join(titles, ", ")
+
+	sort.SliceStable(feedOut.Items, func(i, j int) bool {
+		return ItemDate(feedOut.Items[i]).After(ItemDate(feedOut.Items[j]))
+	})
+
+	return feedOut
+}
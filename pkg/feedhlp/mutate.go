@@ -5,19 +5,20 @@ import (
 
 	"github.com/jlelse/feeds"
 	"github.com/mmcdole/gofeed"
+	ext "github.com/mmcdole/gofeed/extensions"
 )
 
-func MutateFeed(feedIn *gofeed.Feed, mutateFeedItemFunc func(item *feeds.Item) *feeds.Item) *feeds.Feed {
+func MutateFeed(feedIn *gofeed.Feed, mutateFeedItemFunc func(item *Item) *Item) *Feed {
 	currentTime := time.Now()
 
-	feedOut := &feeds.Feed{
-		Title: feedIn.Title,
-		Link: &feeds.Link{
-			Href: feedIn.Link,
-		},
-		Description: feedIn.Description,
-		Copyright:   feedIn.Copyright,
+	feedOut := new(Feed)
+
+	feedOut.Title = feedIn.Title
+	feedOut.Link = &feeds.Link{
+		Href: feedIn.Link,
 	}
+	feedOut.Description = feedIn.Description
+	feedOut.Copyright = feedIn.Copyright
 
 	if feedIn.PublishedParsed != nil {
 		feedOut.Created = *feedIn.PublishedParsed
@@ -30,8 +31,10 @@ func MutateFeed(feedIn *gofeed.Feed, mutateFeedItemFunc func(item *feeds.Item) *
 	if len(feedIn.Authors) > 0 {
 		author := feedIn.Authors[0]
 		if author != nil {
-			feedOut.Author.Name = author.Name
-			feedOut.Author.Email = author.Email
+			feedOut.Author = &feeds.Author{
+				Name:  author.Name,
+				Email: author.Email,
+			}
 		}
 	}
 
@@ -43,56 +46,135 @@ func MutateFeed(feedIn *gofeed.Feed, mutateFeedItemFunc func(item *feeds.Item) *
 		}
 	}
 
-	feedOut.Items = make([]*feeds.Item, 0, len(feedIn.Items))
+	feedOut.Items = make([]*Item, 0, len(feedIn.Items))
 
 	for _, el := range feedIn.Items {
 		if el == nil {
 			continue
 		}
 
-		item := new(feeds.Item)
+		item := ConvertItem(el)
 
-		item.Id = el.GUID
-		item.Title = el.Title
-		item.Description = el.Description
-		item.Link = &feeds.Link{
-			Href: el.Link,
+		item = mutateFeedItemFunc(item)
+		if item == nil {
+			continue
 		}
 
-		var created, updated time.Time
+		feedOut.Items = append(feedOut.Items, item)
+	}
+
+	return feedOut
+}
+
+// ConvertItem converts gofeed item to feed item keeping
+// categories, enclosures, full content, comments link and image.
+func ConvertItem(el *gofeed.Item) *Item {
+	item := new(Item)
+
+	item.Id = el.GUID
+	item.Title = el.Title
+	item.Description = el.Description
+	item.Content = el.Content
+	item.Link = &feeds.Link{
+		Href: el.Link,
+	}
+
+	var created, updated time.Time
+
+	if el.PublishedParsed != nil {
+		created = *el.PublishedParsed
+	}
+
+	if el.UpdatedParsed != nil {
+		updated = *el.UpdatedParsed
+	}
+
+	if !created.IsZero() {
+		item.Created = created
+	}
+
+	if !updated.IsZero() {
+		item.Updated = updated
+	}
+
+	author := new(feeds.Author)
+
+	if el.Author != nil {
+		author.Name = el.Author.Name
+		author.Email = el.Author.Email
+	}
+
+	item.Author = author
+
+	// upstream feed may be shared between requests, do not share slices with it
+	item.Categories = append([]string(nil), el.Categories...)
 
-		if el.PublishedParsed != nil {
-			created = *el.PublishedParsed
+	for _, enc := range el.Enclosures {
+		if enc == nil || enc.URL == "" {
+			continue
 		}
 
-		if el.UpdatedParsed != nil {
-			updated = *el.UpdatedParsed
+		item.Enclosures = append(item.Enclosures, &feeds.Enclosure{
+			Url:    enc.URL,
+			Length: enc.Length,
+			Type:   enc.Type,
+		})
+	}
+
+	if el.Image != nil && el.Image.URL != "" {
+		item.Image = &feeds.Image{
+			Url:   el.Image.URL,
+			Title: el.Image.Title,
 		}
+	} else if thumbnail := mediaThumbnailURL(el.Extensions); thumbnail != "" {
+		item.Image = &feeds.Image{
+			Url: thumbnail,
+		}
+	}
+
+	item.Comments = el.Custom[CustomKeyComments]
+
+	return item
+}
 
-		if !created.IsZero() {
-			item.Created = created
+// mediaThumbnailURL returns URL of `media:thumbnail` (or `media:group/media:thumbnail`)
+// item element, gofeed does not translate it to item image.
+func mediaThumbnailURL(extensions ext.Extensions) string {
+	media := extensions[mediaPrefix]
+
+	for _, el := range media[mediaElementThumbnail] {
+		if src := el.Attrs["url"]; src != "" {
+			return src
 		}
+	}
 
-		if !updated.IsZero() {
-			item.Updated = updated
+	for _, group := range media[mediaElementGroup] {
+		for _, el := range group.Children[mediaElementThumbnail] {
+			if src := el.Attrs["url"]; src != "" {
+				return src
+			}
 		}
+	}
 
-		author := new(feeds.Author)
+	return ""
+}
 
-		if el.Author != nil {
-			author.Name = el.Author.Name
-			author.Email = el.Author.Email
-		}
+// MutateItems applies mutate function to every feed item, nil result drops item.
+func MutateItems(feed *Feed, mutateFeedItemFunc func(item *Item) *Item) {
+	items := make([]*Item, 0, len(feed.Items))
 
-		item.Author = author
+	for _, el := range feed.Items {
+		if el == nil {
+			continue
+		}
 
-		item = mutateFeedItemFunc(item)
+		item := mutateFeedItemFunc(el)
 		if item == nil {
 			continue
 		}
 
-		feedOut.Items = append(feedOut.Items, item)
+		items = append(items, item)
 	}
 
-	return feedOut
+	feed.Items = items
 }
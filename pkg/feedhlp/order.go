@@ -0,0 +1,60 @@
+package feedhlp
+
+import (
+	"sort"
+	"strings"
+)
+
+const (
+	SortByDate      = "date"
+	SortByDateDesc  = "-date"
+	SortByTitle     = "title"
+	SortByTitleDesc = "-title"
+)
+
+// SortItems sorts feed items, order of equal items is preserved.
+func SortItems(feed *Feed, by string) {
+	var less func(a, b *Item) bool
+
+	switch by {
+	case SortByDate:
+		less = func(a, b *Item) bool {
+			return ItemDate(a).Before(ItemDate(b))
+		}
+	case SortByDateDesc:
+		less = func(a, b *Item) bool {
+			return ItemDate(a).After(ItemDate(b))
+		}
+	case SortByTitle:
+		less = func(a, b *Item) bool {
+			return strings.ToLower(a.Title) < strings.ToLower(b.Title)
+		}
+	case SortByTitleDesc:
+		less = func(a, b *Item) bool {
+			return strings.ToLower(a.Title) > strings.ToLower(b.Title)
+		}
+	default:
+		return
+	}
+
+	sort.SliceStable(feed.Items, func(i, j int) bool {
+		return less(feed.Items[i], feed.Items[j])
+	})
+}
+
+// SliceItems skips `offset` items and keeps up to `limit` items, zero limit means no limit.
+func SliceItems(feed *Feed, offset, limit int) {
+	if offset >= len(feed.Items) {
+		feed.Items = feed.Items[:0]
+
+		return
+	}
+
+	if offset > 0 {
+		feed.Items = feed.Items[offset:]
+	}
+
+	if limit > 0 && limit < len(feed.Items) {
+		feed.Items = feed.Items[:limit]
+	}
+}
@@ -0,0 +1,48 @@
+package feedhlp
+
+import (
+	"github.com/mmcdole/gofeed"
+	"github.com/mmcdole/gofeed/rss"
+)
+
+const CustomKeyComments = "comments"
+
+// rssTranslator keeps RSS item fields that universal gofeed.Item does not have.
+type rssTranslator struct {
+	gofeed.DefaultRSSTranslator
+}
+
+func (t *rssTranslator) Translate(feed any) (*gofeed.Feed, error) {
+	out, err := t.DefaultRSSTranslator.Translate(feed)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // pass gofeed error unwrapped
+	}
+
+	in, ok := feed.(*rss.Feed)
+	if !ok || len(in.Items) != len(out.Items) {
+		return out, nil
+	}
+
+	for i, el := range in.Items {
+		if el == nil || out.Items[i] == nil || el.Comments == "" {
+			continue
+		}
+
+		if out.Items[i].Custom == nil {
+			out.Items[i].Custom = make(map[string]string)
+		}
+
+		out.Items[i].Custom[CustomKeyComments] = el.Comments
+	}
+
+	return out, nil
+}
+
+// NewParser creates gofeed parser that preserves RSS item comments link in `Custom` map.
+func NewParser() *gofeed.Parser {
+	fp := gofeed.NewParser()
+
+	fp.RSSTranslator = new(rssTranslator)
+
+	return fp
+}
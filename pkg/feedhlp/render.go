@@ -0,0 +1,188 @@
+package feedhlp
+
+import (
+	"encoding/xml"
+	"strconv"
+	"strings"
+
+	"github.com/jlelse/feeds"
+)
+
+const (
+	mediaNamespace        = "http://search.yahoo.com/mrss/"
+	mediaPrefix           = "media"
+	mediaElementThumbnail = "thumbnail"
+	mediaElementGroup     = "group"
+
+	defaultEnclosureLength = "0"
+)
+
+// Wrappers around jlelse/feeds XML objects, outer fields take
+// precedence over embedded ones with the same XML name.
+
+type mediaThumbnail struct {
+	XMLName xml.Name `xml:"media:thumbnail"`
+	URL     string   `xml:"url,attr"`
+}
+
+type rssItem struct {
+	*feeds.RssItem
+	Thumbnail  *mediaThumbnail
+	Categories []string `xml:"category"`
+}
+
+type rssChannel struct {
+	*feeds.RssFeed
+	Items []*rssItem `xml:"item"`
+}
+
+type rssFeedXML struct {
+	XMLName          xml.Name `xml:"rss"`
+	Channel          *rssChannel
+	Version          string `xml:"version,attr"`
+	ContentNamespace string `xml:"xmlns:content,attr"`
+	MediaNamespace   string `xml:"xmlns:media,attr"`
+}
+
+type atomCategory struct {
+	XMLName xml.Name `xml:"category"`
+	Term    string   `xml:"term,attr"`
+}
+
+type atomEntry struct {
+	*feeds.AtomEntry
+	Thumbnail  *mediaThumbnail
+	Categories []atomCategory `xml:"category"`
+}
+
+type atomFeed struct {
+	*feeds.AtomFeed
+	MediaNamespace string       `xml:"xmlns:media,attr"`
+	Entries        []*atomEntry `xml:"entry"`
+}
+
+func toXML(v any) (string, error) {
+	data, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return "", err //nolint:wrapcheck // pass encoding/xml error unwrapped
+	}
+
+	// strip empty line from default xml header
+	return xml.Header[:len(xml.Header)-1] + string(data), nil
+}
+
+func (f *Feed) ToRss() (string, error) {
+	channel := &rssChannel{
+		RssFeed: (&feeds.Rss{Feed: f.toFeeds()}).RssFeed(),
+		Items:   make([]*rssItem, 0, len(f.Items)),
+	}
+
+	for i, el := range channel.RssFeed.Items {
+		item := &rssItem{
+			RssItem:    el,
+			Categories: f.Items[i].Categories,
+		}
+
+		item.Comments = f.Items[i].Comments
+
+		if len(f.Items[i].Enclosures) > 0 {
+			// RSS 2.0 allows single enclosure per item
+			enc := f.Items[i].Enclosures[0]
+
+			item.RssItem.Enclosure = &feeds.RssEnclosure{
+				Url:    enc.Url,
+				Length: enc.Length,
+				Type:   enc.Type,
+			}
+
+			if item.RssItem.Enclosure.Length == "" {
+				item.RssItem.Enclosure.Length = defaultEnclosureLength
+			}
+		}
+
+		if f.Items[i].Image != nil {
+			item.Thumbnail = &mediaThumbnail{URL: f.Items[i].Image.Url}
+		}
+
+		channel.Items = append(channel.Items, item)
+	}
+
+	return toXML(&rssFeedXML{
+		Version:          "2.0",
+		Channel:          channel,
+		ContentNamespace: "http://purl.org/rss/1.0/modules/content/",
+		MediaNamespace:   mediaNamespace,
+	})
+}
+
+func (f *Feed) ToAtom() (string, error) {
+	feed := &atomFeed{
+		AtomFeed:       (&feeds.Atom{Feed: f.toFeeds()}).AtomFeed(),
+		MediaNamespace: mediaNamespace,
+		Entries:        make([]*atomEntry, 0, len(f.Items)),
+	}
+
+	for i, el := range feed.AtomFeed.Entries {
+		entry := &atomEntry{
+			AtomEntry:  el,
+			Categories: make([]atomCategory, 0, len(f.Items[i].Categories)),
+		}
+
+		for _, category := range f.Items[i].Categories {
+			entry.Categories = append(entry.Categories, atomCategory{Term: category})
+		}
+
+		for _, enc := range f.Items[i].Enclosures {
+			entry.Links = append(entry.Links, feeds.AtomLink{
+				Href:   enc.Url,
+				Rel:    "enclosure",
+				Type:   enc.Type,
+				Length: enc.Length,
+			})
+		}
+
+		if f.Items[i].Comments != "" {
+			entry.Links = append(entry.Links, feeds.AtomLink{
+				Href: f.Items[i].Comments,
+				Rel:  "replies",
+				Type: "text/html",
+			})
+		}
+
+		if f.Items[i].Image != nil {
+			entry.Thumbnail = &mediaThumbnail{URL: f.Items[i].Image.Url}
+		}
+
+		feed.Entries = append(feed.Entries, entry)
+	}
+
+	return toXML(feed)
+}
+
+func (f *Feed) ToJSON() (string, error) {
+	feed := (&feeds.JSON{Feed: f.toFeeds()}).JSONFeed()
+
+	for i, el := range feed.Items {
+		el.Tags = f.Items[i].Categories
+
+		for _, enc := range f.Items[i].Enclosures {
+			size, _ := strconv.ParseInt(enc.Length, 10, 32)
+
+			el.Attachments = append(el.Attachments, feeds.JSONAttachment{
+				Url:      enc.Url,
+				MIMEType: enc.Type,
+				Size:     int32(size),
+			})
+
+			if el.Image == "" && strings.HasPrefix(enc.Type, "image/") {
+				el.Image = enc.Url
+			}
+		}
+
+		if f.Items[i].Image != nil {
+			el.Image = f.Items[i].Image.Url
+		}
+	}
+
+	return feed.ToJSON() //nolint:wrapcheck // pass jlelse/feeds error unwrapped
+}
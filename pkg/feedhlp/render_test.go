@@ -0,0 +1,128 @@
+package feedhlp_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/s3rj1k/yafp/pkg/feedhlp"
+	"github.com/stretchr/testify/assert"
+)
+
+const testRSS = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:content="http://purl.org/rss/1.0/modules/content/" xmlns:media="http://search.yahoo.com/mrss/">
+  <channel>
+    <title>Test feed</title>
+    <link>https://example.com/</link>
+    <description>Test feed description</description>
+    <item>
+      <title>Episode 1</title>
+      <link>https://example.com/1</link>
+      <guid>https://example.com/1</guid>
+      <description>Short description</description>
+      <content:encoded><![CDATA[<p>Full content</p>]]></content:encoded>
+      <category>Podcast</category>
+      <category>Tech</category>
+      <comments>https://example.com/1#comments</comments>
+      <enclosure url="https://cdn.example.com/1.mp3" length="12345" type="audio/mpeg"/>
+      <media:thumbnail url="https://cdn.example.com/1.jpg"/>
+      <pubDate>Mon, 01 Jan 2024 10:00:00 +0000</pubDate>
+    </item>
+  </channel>
+</rss>`
+
+func keepItem(item *feedhlp.Item) *feedhlp.Item {
+	return item
+}
+
+func TestRenderRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	feedIn, err := feedhlp.NewParser().Parse(strings.NewReader(testRSS))
+	assert.NoError(t, err)
+
+	feed := feedhlp.MutateFeed(feedIn, keepItem)
+
+	tests := []struct {
+		name   string
+		render func() (string, error)
+		// rendered feed must contain these strings
+		contains []string
+	}{
+		{
+			name:   "rss",
+			render: feed.ToRss,
+			contains: []string{
+				`<content:encoded><![CDATA[<p>Full content</p>]]></content:encoded>`,
+				`<comments>https://example.com/1#comments</comments>`,
+				`<media:thumbnail url="https://cdn.example.com/1.jpg"></media:thumbnail>`,
+				`length="12345"`,
+			},
+		},
+		{
+			name:   "atom",
+			render: feed.ToAtom,
+			contains: []string{
+				`<content type="html">&lt;p&gt;Full content&lt;/p&gt;</content>`,
+				`href="https://example.com/1#comments" rel="replies"`,
+				`<media:thumbnail url="https://cdn.example.com/1.jpg"></media:thumbnail>`,
+				`length="12345"`,
+			},
+		},
+		{
+			name:   "json",
+			render: feed.ToJSON,
+			contains: []string{
+				`"size": 12345`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			out, err := tt.render()
+			assert.NoError(t, err)
+
+			for _, el := range tt.contains {
+				assert.Contains(t, out, el)
+			}
+
+			// rendered feed is read back same way upstream feeds are
+			feedOut, err := feedhlp.NewParser().Parse(strings.NewReader(out))
+			assert.NoError(t, err)
+			assert.Len(t, feedOut.Items, 1)
+
+			item := feedhlp.ConvertItem(feedOut.Items[0])
+
+			assert.Equal(t, "Episode 1", item.Title)
+			assert.Equal(t, "<p>Full content</p>", item.Content)
+			assert.Equal(t, []string{"Podcast", "Tech"}, item.Categories)
+
+			if assert.Len(t, item.Enclosures, 1) {
+				assert.Equal(t, "https://cdn.example.com/1.mp3", item.Enclosures[0].Url)
+				assert.Equal(t, "audio/mpeg", item.Enclosures[0].Type)
+			}
+
+			if assert.NotNil(t, item.Image) {
+				assert.Equal(t, "https://cdn.example.com/1.jpg", item.Image.Url)
+			}
+		})
+	}
+}
+
+func TestParserComments(t *testing.T) {
+	t.Parallel()
+
+	feedIn, err := feedhlp.NewParser().Parse(strings.NewReader(testRSS))
+	assert.NoError(t, err)
+
+	item := feedhlp.ConvertItem(feedIn.Items[0])
+
+	assert.Equal(t, "https://example.com/1#comments", item.Comments)
+	assert.Equal(t, "https://cdn.example.com/1.jpg", item.Image.Url)
+	assert.Equal(t, "Short description", item.Description)
+	assert.Equal(t, "<p>Full content</p>", item.Content)
+}
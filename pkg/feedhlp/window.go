@@ -0,0 +1,39 @@
+package feedhlp
+
+import (
+	"time"
+)
+
+// ItemDate returns item publication date, falls back to update date.
+func ItemDate(item *Item) time.Time {
+	if !item.Created.IsZero() {
+		return item.Created
+	}
+
+	return item.Updated
+}
+
+// FilterByDate drops items published outside of [since, until] window,
+// zero bound is treated as unbounded.
+func FilterByDate(feed *Feed, since, until time.Time, keepUndated bool) {
+	items := make([]*Item, 0, len(feed.Items))
+
+	for _, el := range feed.Items {
+		date := ItemDate(el)
+
+		switch {
+		case date.IsZero():
+			if !keepUndated {
+				continue
+			}
+		case !since.IsZero() && date.Before(since):
+			continue
+		case !until.IsZero() && date.After(until):
+			continue
+		}
+
+		items = append(items, el)
+	}
+
+	feed.Items = items
+}
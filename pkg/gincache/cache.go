@@ -2,8 +2,10 @@ package gincache
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -11,7 +13,23 @@ import (
 	"golang.org/x/sync/singleflight"
 )
 
-func getCacheKey(c *gin.Context) (string, error) {
+type refreshKey struct{}
+
+// WithRefresh marks request context, response to such request is not served
+// from cache but is stored in cache. Clients can not set context values.
+func WithRefresh(ctx context.Context) context.Context {
+	return context.WithValue(ctx, refreshKey{}, true)
+}
+
+// Vary is request header that response representation depends on, normalize (may be nil)
+// maps header value to representation, so values that select same representation
+// (e.g. different `Accept` headers of one format) share cached response.
+type Vary struct {
+	Header    string
+	Normalize func(value string) string
+}
+
+func getCacheKey(c *gin.Context, vary []Vary) (string, error) {
 	if c == nil {
 		return "", fmt.Errorf("undefined server context")
 	}
@@ -24,7 +42,21 @@ func getCacheKey(c *gin.Context) (string, error) {
 		return "", fmt.Errorf("undefined request URL object")
 	}
 
-	return c.Request.URL.String(), nil
+	// response may contain absolute URLs built from request host (e.g. image proxy URLs)
+	key := c.Request.Host + c.Request.URL.RequestURI()
+
+	// response representation depends on values of listed request headers
+	for _, el := range vary {
+		val := c.Request.Header.Get(el.Header)
+
+		if el.Normalize != nil {
+			val = el.Normalize(val)
+		}
+
+		key += fmt.Sprintf("\n%s: %s", http.CanonicalHeaderKey(el.Header), val)
+	}
+
+	return key, nil
 }
 
 func isCacheble(c *gin.Context, rcw *ResponseCacheWriter) bool {
@@ -54,20 +86,36 @@ func getDynamicTTLValue(item *ttlcache.Item[string, any]) string {
 
 // Original code by: https://github.com/chenyahui/gin-cache
 
+// Cache caches responses per request host, URL and (normalized) values of `vary` request headers,
+// only `GET` and `HEAD` requests are served from cache.
 func Cache(
 	cache *ttlcache.Cache[string, any],
 	recordTTL, singleFlightForgetTimerDuration time.Duration,
+	vary ...Vary,
 ) gin.HandlerFunc {
 	sfg := new(singleflight.Group)
 
+	varyHeaders := make([]string, 0, len(vary))
+
+	for _, el := range vary {
+		varyHeaders = append(varyHeaders, http.CanonicalHeaderKey(el.Header))
+	}
+
 	return func(c *gin.Context) {
-		cacheKey, err := getCacheKey(c)
+		// requests with body (e.g. POST) are neither cached nor coalesced
+		if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+			c.Next()
+
+			return
+		}
+
+		cacheKey, err := getCacheKey(c, vary)
 		if err != nil {
 			panic(err)
 		}
 
 		item := cache.Get(cacheKey, ttlcache.WithDisableTouchOnHit[string, any]())
-		if item != nil {
+		if item != nil && c.Request.Context().Value(refreshKey{}) == nil {
 			if cachedResponse, ok := item.Value().(*CachedResponse); !item.IsExpired() && ok {
 				cachedResponse.Send(c,
 					HTTPHeader{
@@ -92,6 +140,10 @@ func Cache(
 		}
 		c.Writer = rcw
 
+		if len(varyHeaders) > 0 {
+			c.Header("Vary", strings.Join(varyHeaders, ", "))
+		}
+
 		var inFlight bool
 
 		cachedResponseObj, err, _ := sfg.Do(cacheKey, func() (any, error) {
@@ -5,6 +5,7 @@ import (
 	"math/rand"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -70,6 +71,36 @@ func TestCache(t *testing.T) {
 	assert.Equal(t, "uid:u4", w4.Body.String())
 }
 
+func TestCacheHost(t *testing.T) {
+	t.Parallel()
+
+	cache := ttlcache.New[string, any](
+		ttlcache.WithTTL[string, any](time.Minute),
+	)
+
+	cacheMiddleware := gincache.Cache(cache, 3*time.Second, defaultSingleFlightForgetTimerDuration)
+
+	request := func(host string) *httptest.ResponseRecorder {
+		testWriter := httptest.NewRecorder()
+
+		_, engine := gin.CreateTestContext(testWriter)
+		engine.Use(cacheMiddleware)
+		engine.GET("/cache", func(c *gin.Context) {
+			c.String(http.StatusOK, "http://%s/img", c.Request.Host)
+		})
+
+		testRequest := httptest.NewRequest(http.MethodGet, "/cache", nil)
+		testRequest.Host = host
+
+		engine.ServeHTTP(testWriter, testRequest)
+
+		return testWriter
+	}
+
+	assert.Equal(t, "http://evil.example/img", request("evil.example").Body.String())
+	assert.Equal(t, "http://good.example/img", request("good.example").Body.String())
+}
+
 func TestCacheDuration(t *testing.T) {
 	t.Parallel()
 
@@ -174,3 +205,156 @@ func TestWriteHeader(t *testing.T) {
 	engine.ServeHTTP(testWriter, testRequest)
 	assert.Equal(t, "world", testWriter.Header().Get("hello"))
 }
+
+func TestVaryHeader(t *testing.T) {
+	t.Parallel()
+
+	cache := ttlcache.New[string, any](
+		ttlcache.WithTTL[string, any](time.Minute),
+	)
+
+	cacheMiddleware := gincache.Cache(cache, 3*time.Second, defaultSingleFlightForgetTimerDuration,
+		gincache.Vary{Header: "Accept"})
+
+	request := func(accept string) *httptest.ResponseRecorder {
+		testWriter := httptest.NewRecorder()
+
+		_, engine := gin.CreateTestContext(testWriter)
+		engine.Use(cacheMiddleware)
+		engine.GET("/cache", func(c *gin.Context) {
+			c.String(http.StatusOK, "accept:%s,rand:%d", c.GetHeader("Accept"), rand.Int()) //nolint:gosec // no need for secure random number generator
+		})
+
+		testRequest := httptest.NewRequest(http.MethodGet, "/cache", nil)
+		testRequest.Header.Set("Accept", accept)
+
+		engine.ServeHTTP(testWriter, testRequest)
+
+		return testWriter
+	}
+
+	w1 := request("application/rss+xml")
+	w2 := request("application/rss+xml")
+	w3 := request("application/feed+json")
+
+	assert.Equal(t, w1.Body, w2.Body)
+	assert.NotEqual(t, w1.Body, w3.Body)
+	assert.Equal(t, "Accept", w1.Header().Get("Vary"))
+	assert.Equal(t, "Accept", w2.Header().Get("Vary"))
+}
+
+func TestVaryNormalize(t *testing.T) {
+	t.Parallel()
+
+	cache := ttlcache.New[string, any](
+		ttlcache.WithTTL[string, any](time.Minute),
+	)
+
+	// header values are reduced to format they select
+	cacheMiddleware := gincache.Cache(cache, 3*time.Second, defaultSingleFlightForgetTimerDuration,
+		gincache.Vary{
+			Header: "Accept",
+			Normalize: func(value string) string {
+				if strings.Contains(value, "json") {
+					return "json"
+				}
+
+				return "xml"
+			},
+		})
+
+	request := func(accept string) *httptest.ResponseRecorder {
+		testWriter := httptest.NewRecorder()
+
+		_, engine := gin.CreateTestContext(testWriter)
+		engine.Use(cacheMiddleware)
+		engine.GET("/cache", func(c *gin.Context) {
+			c.String(http.StatusOK, "rand:%d", rand.Int()) //nolint:gosec // no need for secure random number generator
+		})
+
+		testRequest := httptest.NewRequest(http.MethodGet, "/cache", nil)
+		testRequest.Header.Set("Accept", accept)
+
+		engine.ServeHTTP(testWriter, testRequest)
+
+		return testWriter
+	}
+
+	w1 := request("application/rss+xml")
+	w2 := request("application/rss+xml;q=0.9, text/html, x-random/1")
+	w3 := request("application/feed+json")
+	w4 := request("application/json;q=0.5")
+
+	assert.Equal(t, w1.Body, w2.Body)
+	assert.Equal(t, w3.Body, w4.Body)
+	assert.NotEqual(t, w1.Body, w3.Body)
+	assert.Equal(t, "Accept", w1.Header().Get("Vary"))
+}
+
+func TestBypassUnsafeMethods(t *testing.T) {
+	t.Parallel()
+
+	cache := ttlcache.New[string, any](
+		ttlcache.WithTTL[string, any](time.Minute),
+	)
+
+	cacheMiddleware := gincache.Cache(cache, 3*time.Second, defaultSingleFlightForgetTimerDuration)
+
+	request := func(method string) *httptest.ResponseRecorder {
+		testWriter := httptest.NewRecorder()
+
+		_, engine := gin.CreateTestContext(testWriter)
+		engine.Use(cacheMiddleware)
+		engine.Handle(method, "/cache", func(c *gin.Context) {
+			c.String(http.StatusOK, "method:%s,rand:%d", c.Request.Method, rand.Int()) //nolint:gosec // no need for secure random number generator
+		})
+
+		engine.ServeHTTP(testWriter, httptest.NewRequest(method, "/cache", nil))
+
+		return testWriter
+	}
+
+	w1 := request(http.MethodGet)
+	w2 := request(http.MethodPost)
+	w3 := request(http.MethodPost)
+
+	assert.NotEqual(t, w1.Body, w2.Body)
+	assert.NotEqual(t, w2.Body, w3.Body)
+	assert.Empty(t, w2.Header().Get("X-Cache"))
+}
+
+func TestRefresh(t *testing.T) {
+	t.Parallel()
+
+	cache := ttlcache.New[string, any](
+		ttlcache.WithTTL[string, any](time.Minute),
+	)
+
+	cacheMiddleware := gincache.Cache(cache, 3*time.Second, defaultSingleFlightForgetTimerDuration)
+
+	request := func(refresh bool) *httptest.ResponseRecorder {
+		testWriter := httptest.NewRecorder()
+
+		_, engine := gin.CreateTestContext(testWriter)
+		engine.Use(cacheMiddleware)
+		engine.GET("/cache", func(c *gin.Context) {
+			c.String(http.StatusOK, "rand:%d", rand.Int()) //nolint:gosec // no need for secure random number generator
+		})
+
+		testRequest := httptest.NewRequest(http.MethodGet, "/cache", nil)
+		if refresh {
+			testRequest = testRequest.WithContext(gincache.WithRefresh(testRequest.Context()))
+		}
+
+		engine.ServeHTTP(testWriter, testRequest)
+
+		return testWriter
+	}
+
+	w1 := request(false)
+	w2 := request(true)
+	w3 := request(false)
+
+	assert.NotEqual(t, w1.Body, w2.Body)
+	assert.Equal(t, w2.Body, w3.Body)
+}
@@ -0,0 +1,7 @@
+package htmlscrapper
+
+import (
+	"errors"
+)
+
+var ErrNoItems = errors.New("no items found")
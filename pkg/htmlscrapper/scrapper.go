@@ -0,0 +1,146 @@
+package htmlscrapper
+
+import (
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/araddon/dateparse"
+	"github.com/s3rj1k/yafp/pkg/tgscrapper"
+)
+
+// Selectors describe page structure with CSS selectors, all selectors
+// except `Item` and `Next` are relative to item container.
+type Selectors struct {
+	Item  string `form:"item" json:"item" binding:"required,selector"`
+	Title string `form:"title" json:"title" binding:"omitempty,selector"`
+	Link  string `form:"link" json:"link" binding:"omitempty,selector"`
+	Date  string `form:"date" json:"date" binding:"omitempty,selector"`
+	Body  string `form:"body" json:"body" binding:"omitempty,selector"`
+	Next  string `form:"next" json:"next" binding:"omitempty,selector"`
+}
+
+type Item struct {
+	Date time.Time
+
+	Title string
+	Body  string
+	Link  string
+}
+
+type Page struct {
+	Title       string
+	Description string
+
+	Items []*Item
+
+	// Next is absolute URL of the next page, empty when undefined.
+	Next string
+}
+
+func resolve(base *url.URL, val string) string {
+	val = strings.TrimSpace(val)
+
+	if base == nil || val == "" {
+		return val
+	}
+
+	u, err := base.Parse(val)
+	if err != nil {
+		return ""
+	}
+
+	return u.String()
+}
+
+// find returns first match of selector in selection, empty selector matches selection itself.
+func find(selection *goquery.Selection, selector string) *goquery.Selection {
+	if selector == "" {
+		return selection
+	}
+
+	return selection.Find(selector).First()
+}
+
+func getLink(selection *goquery.Selection, selector string) string {
+	el := find(selection, selector)
+
+	if val, ok := el.Attr("href"); ok {
+		return val
+	}
+
+	val, _ := el.Find("a[href]").First().Attr("href")
+
+	return val
+}
+
+func getDate(selection *goquery.Selection, selector string) time.Time {
+	el := find(selection, selector)
+
+	val, ok := el.Attr("datetime")
+	if !ok {
+		if val, ok = el.Find("[datetime]").First().Attr("datetime"); !ok {
+			val = el.Text()
+		}
+	}
+
+	tt, err := dateparse.ParseAny(strings.TrimSpace(val))
+	if err != nil {
+		return time.Time{}
+	}
+
+	return tt.UTC().Round(time.Second)
+}
+
+func getTitle(selection *goquery.Selection, selector string) string {
+	text := strings.TrimSpace(tgscrapper.Textify(find(selection, selector)))
+	if text == "" {
+		return ""
+	}
+
+	return tgscrapper.Ellipsize(text)
+}
+
+// Parse extracts items from document, relative links are resolved against
+// document URL (when defined). Items without title are skipped.
+func Parse(doc *goquery.Document, sel *Selectors) (*Page, error) {
+	page := &Page{
+		Title: strings.TrimSpace(doc.Find("title").First().Text()),
+		Items: make([]*Item, 0),
+	}
+
+	page.Description, _ = doc.Find("meta[name='description']").First().Attr("content")
+
+	doc.Find(sel.Item).Each(func(_ int, selection *goquery.Selection) {
+		item := &Item{
+			Title: getTitle(selection, sel.Title),
+			Link:  resolve(doc.Url, getLink(selection, sel.Link)),
+		}
+
+		if item.Title == "" {
+			return
+		}
+
+		if sel.Date != "" {
+			item.Date = getDate(selection, sel.Date)
+		}
+
+		body, err := find(selection, sel.Body).Html()
+		if err == nil {
+			item.Body = strings.TrimSpace(body)
+		}
+
+		page.Items = append(page.Items, item)
+	})
+
+	if sel.Next != "" {
+		page.Next = resolve(doc.Url, getLink(doc.Selection, sel.Next))
+	}
+
+	if len(page.Items) == 0 {
+		return page, ErrNoItems
+	}
+
+	return page, nil
+}
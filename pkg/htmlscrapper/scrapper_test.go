@@ -0,0 +1,55 @@
+package htmlscrapper_test
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/s3rj1k/yafp/pkg/htmlscrapper"
+	"github.com/stretchr/testify/assert"
+)
+
+const page = `<html><head><title>News</title></head><body>
+<div class="post"><h2><a href="/p/1">First post</a></h2><time datetime="2022-05-01T10:00:00Z">May 1</time><div class="text"><p>One</p></div></div>
+<div class="post"><h2><a href="https://other.example.com/2">Second post</a></h2><span class="date">2022-05-02</span><div class="text"><p>Two</p></div></div>
+<div class="post"><h2></h2></div>
+<a class="next" href="?page=2">Older</a>
+</body></html>`
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(page))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	doc.Url, _ = url.Parse("https://example.com/news/")
+
+	out, err := htmlscrapper.Parse(doc, &htmlscrapper.Selectors{
+		Item:  "div.post",
+		Title: "h2",
+		Link:  "h2 a",
+		Date:  "time, .date",
+		Body:  ".text",
+		Next:  "a.next",
+	})
+	if !assert.NoError(t, err) || !assert.Len(t, out.Items, 2) {
+		return
+	}
+
+	assert.Equal(t, "News", out.Title)
+	assert.Equal(t, "https://example.com/news/?page=2", out.Next)
+
+	assert.Equal(t, "First post", out.Items[0].Title)
+	assert.Equal(t, "https://example.com/p/1", out.Items[0].Link)
+	assert.Equal(t, "2022-05-01T10:00:00Z", out.Items[0].Date.Format("2006-01-02T15:04:05Z07:00"))
+	assert.Equal(t, "<p>One</p>", out.Items[0].Body)
+
+	assert.Equal(t, "https://other.example.com/2", out.Items[1].Link)
+	assert.Equal(t, 2022, out.Items[1].Date.Year())
+
+	_, err = htmlscrapper.Parse(doc, &htmlscrapper.Selectors{Item: "article"})
+	assert.ErrorIs(t, err, htmlscrapper.ErrNoItems)
+}
@@ -0,0 +1,97 @@
+package imgproxy
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+const (
+	QueryParamURL       = "url"
+	QueryParamSignature = "sig"
+)
+
+// Sign returns URL-safe HMAC-SHA256 signature of image URL.
+func Sign(key []byte, imageURL string) string {
+	mac := hmac.New(sha256.New, key)
+	_, _ = mac.Write([]byte(imageURL))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether signature is valid for image URL.
+func Verify(key []byte, imageURL, signature string) bool {
+	val, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, key)
+	_, _ = mac.Write([]byte(imageURL))
+
+	return hmac.Equal(val, mac.Sum(nil))
+}
+
+// URL returns signed proxy URL for image, endpoint is absolute URL of proxy route.
+// Image URL that is not absolute HTTP(S) URL is returned unchanged.
+func URL(endpoint string, key []byte, imageURL string) string {
+	u, err := url.Parse(imageURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return imageURL
+	}
+
+	return endpoint + "?" + url.Values{
+		QueryParamURL:       {imageURL},
+		QueryParamSignature: {Sign(key, imageURL)},
+	}.Encode()
+}
+
+func rewriteNode(node *html.Node, rewrite func(src string) string) {
+	if node.Type == html.ElementNode && node.DataAtom == atom.Img {
+		for i, attr := range node.Attr {
+			if attr.Namespace == "" && attr.Key == "src" {
+				node.Attr[i].Val = rewrite(attr.Val)
+			}
+		}
+	}
+
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		rewriteNode(child, rewrite)
+	}
+}
+
+// RewriteHTML replaces `src` attribute of every image in HTML fragment with result
+// of rewrite function, input that is not parsable is returned unchanged.
+func RewriteHTML(in string, rewrite func(src string) string) string {
+	if !strings.Contains(strings.ToLower(in), "<img") {
+		return in
+	}
+
+	root := &html.Node{
+		Type:     html.ElementNode,
+		Data:     "div",
+		DataAtom: atom.Div,
+	}
+
+	nodes, err := html.ParseFragment(strings.NewReader(in), root)
+	if err != nil {
+		return in
+	}
+
+	var out strings.Builder
+
+	for _, el := range nodes {
+		rewriteNode(el, rewrite)
+
+		if err = html.Render(&out, el); err != nil {
+			return in
+		}
+	}
+
+	return out.String()
+}
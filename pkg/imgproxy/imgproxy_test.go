@@ -0,0 +1,47 @@
+package imgproxy_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/s3rj1k/yafp/pkg/imgproxy"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignedURL(t *testing.T) {
+	t.Parallel()
+
+	key := []byte("secret")
+
+	out := imgproxy.URL("https://proxy/img", key, "https://cdn.example.com/a.jpg?x=1")
+
+	u, err := url.Parse(out)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	imageURL := u.Query().Get(imgproxy.QueryParamURL)
+	signature := u.Query().Get(imgproxy.QueryParamSignature)
+
+	assert.Equal(t, "https://cdn.example.com/a.jpg?x=1", imageURL)
+	assert.True(t, imgproxy.Verify(key, imageURL, signature))
+	assert.False(t, imgproxy.Verify([]byte("other"), imageURL, signature))
+	assert.False(t, imgproxy.Verify(key, "https://evil.example.com/", signature))
+
+	assert.Equal(t, "/relative.png", imgproxy.URL("https://proxy/img", key, "/relative.png"))
+	assert.Equal(t, "data:image/png;base64,AA", imgproxy.URL("https://proxy/img", key, "data:image/png;base64,AA"))
+}
+
+func TestRewriteHTML(t *testing.T) {
+	t.Parallel()
+
+	rewrite := func(src string) string {
+		return "P:" + src
+	}
+
+	assert.Equal(t, `no images`, imgproxy.RewriteHTML(`no images`, rewrite))
+	assert.Equal(t,
+		`<p>a <img src="P:https://x/1.png" alt="1"/></p><img src="P:https://x/2.png"/>`,
+		imgproxy.RewriteHTML(`<p>a <img src="https://x/1.png" alt="1"></p><IMG SRC="https://x/2.png">`, rewrite),
+	)
+}
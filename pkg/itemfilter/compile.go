@@ -0,0 +1,69 @@
+package itemfilter
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/jellydator/ttlcache/v3"
+	"github.com/s3rj1k/yafp/pkg/cachedregexp"
+)
+
+const (
+	DefaultKeyPrefix = "FILTER:"
+)
+
+// Parse parses filter expression, regular expressions are compiled with provided function.
+func Parse(expr string, compile func(expr string) (*regexp.Regexp, error)) (Expr, error) {
+	tokens, err := lex(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{
+		tokens:  tokens,
+		compile: compile,
+	}
+
+	return p.parse()
+}
+
+// Compile parses filter expression and stores result in cache,
+// regular expressions are compiled using `cachedregexp`.
+func Compile(cache *ttlcache.Cache[string, any], expr string) (Expr, error) {
+	key := fmt.Sprintf("%s%s", DefaultKeyPrefix, expr)
+
+	f := func(key, expr string) (Expr, error) {
+		e, err := Parse(expr, func(expr string) (*regexp.Regexp, error) {
+			return cachedregexp.Compile(cache, expr)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("filter compile error: %w", err)
+		}
+
+		_ = cache.Set(key, e, ttlcache.DefaultTTL)
+
+		return e, nil
+	}
+
+	item := cache.Get(key)
+	if item == nil {
+		return f(key, expr)
+	}
+
+	e, ok := item.Value().(Expr)
+	if !ok {
+		return f(key, expr)
+	}
+
+	return e, nil
+}
+
+func MustCompile(cache *ttlcache.Cache[string, any], expr string) Expr {
+	e, err := Compile(cache, expr)
+	if err != nil {
+		panic(`itemfilter: Compile(` + strconv.Quote(expr) + `): ` + err.Error())
+	}
+
+	return e
+}
@@ -0,0 +1,10 @@
+package itemfilter
+
+import (
+	"errors"
+)
+
+var (
+	ErrSyntax       = errors.New("syntax error")
+	ErrUnknownField = errors.New("unknown field")
+)
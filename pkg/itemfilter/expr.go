@@ -0,0 +1,112 @@
+package itemfilter
+
+import (
+	"regexp"
+	"strings"
+)
+
+const (
+	FieldTitle       = "title"
+	FieldDescription = "description"
+	FieldContent     = "content"
+	FieldAuthor      = "author"
+	FieldLink        = "link"
+	FieldGUID        = "guid"
+	FieldCategory    = "category"
+)
+
+// Fields holds item values by field name, multi-valued
+// fields (like categories) match when any of values matches.
+type Fields map[string][]string
+
+func isKnownField(name string) bool {
+	switch name {
+	case FieldTitle, FieldDescription, FieldContent, FieldAuthor, FieldLink, FieldGUID, FieldCategory:
+		return true
+	}
+
+	return false
+}
+
+type Expr interface {
+	Match(fields Fields) bool
+}
+
+type andExpr struct {
+	left, right Expr
+}
+
+func (e *andExpr) Match(fields Fields) bool {
+	return e.left.Match(fields) && e.right.Match(fields)
+}
+
+type orExpr struct {
+	left, right Expr
+}
+
+func (e *orExpr) Match(fields Fields) bool {
+	return e.left.Match(fields) || e.right.Match(fields)
+}
+
+type notExpr struct {
+	expr Expr
+}
+
+func (e *notExpr) Match(fields Fields) bool {
+	return !e.expr.Match(fields)
+}
+
+type predicateExpr struct {
+	test  func(value string) bool
+	field string
+}
+
+func (e *predicateExpr) Match(fields Fields) bool {
+	for _, val := range fields[e.field] {
+		if e.test(val) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func newRegexpPredicate(field string, re *regexp.Regexp) *predicateExpr {
+	return &predicateExpr{
+		field: field,
+		test:  re.MatchString,
+	}
+}
+
+func newEqualPredicate(field, str string) *predicateExpr {
+	return &predicateExpr{
+		field: field,
+		test: func(value string) bool {
+			return value == str
+		},
+	}
+}
+
+func newContainsPredicate(field, str string) *predicateExpr {
+	return &predicateExpr{
+		field: field,
+		test: func(value string) bool {
+			return strings.Contains(value, str)
+		},
+	}
+}
+
+func newInPredicate(field string, list []string) *predicateExpr {
+	return &predicateExpr{
+		field: field,
+		test: func(value string) bool {
+			for _, el := range list {
+				if value == el {
+					return true
+				}
+			}
+
+			return false
+		},
+	}
+}
@@ -0,0 +1,73 @@
+package itemfilter_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/s3rj1k/yafp/pkg/itemfilter"
+	"github.com/stretchr/testify/assert"
+)
+
+func compile(expr string) (*regexp.Regexp, error) {
+	return regexp.Compile(expr) //nolint:wrapcheck // pass regexp error unwrapped
+}
+
+func TestMatch(t *testing.T) {
+	t.Parallel()
+
+	fields := itemfilter.Fields{
+		itemfilter.FieldTitle:    {"Some Movie (1999) BDRip"},
+		itemfilter.FieldAuthor:   {"uploader"},
+		itemfilter.FieldLink:     {"http://example.com/torrent/1"},
+		itemfilter.FieldCategory: {"Movies", "Foreign"},
+	}
+
+	tests := map[string]bool{
+		`title ~ /\(19\d\d\)/`:                     true,
+		`title ~ /bdrip/`:                          false,
+		`title ~ /bdrip/i`:                         true,
+		`title !~ /bdrip/i`:                        false,
+		`author == "uploader"`:                     true,
+		`author != "uploader"`:                     false,
+		`link contains "/torrent/"`:                true,
+		`category in ["Sport", "Foreign"]`:         true,
+		`category in ['Sport']`:                    false,
+		`title ~ /1999/ AND category in ["Sport"]`: false,
+		`title ~ /1999/ and (category in ["Sport"] or NOT author == "x")`: true,
+		`!(title ~ "Movie") || description contains "x"`:                  false,
+		`title ~ /a\/b/ || guid == ""`:                                    false,
+	}
+
+	for expr, expected := range tests {
+		e, err := itemfilter.Parse(expr, compile)
+		if !assert.NoError(t, err, expr) {
+			continue
+		}
+
+		assert.Equal(t, expected, e.Match(fields), expr)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]error{
+		``:                           itemfilter.ErrSyntax,
+		`title`:                      itemfilter.ErrSyntax,
+		`title ~ /(/`:                itemfilter.ErrSyntax,
+		`title ~ /x/z`:               itemfilter.ErrSyntax,
+		`title == "x`:                itemfilter.ErrSyntax,
+		`(title == "x"`:              itemfilter.ErrSyntax,
+		`title == "x" author == "y"`: itemfilter.ErrSyntax,
+		`category in ["a",]`:         itemfilter.ErrSyntax,
+		`size == "1"`:                itemfilter.ErrUnknownField,
+		`title == "x" AND NOT`:       itemfilter.ErrSyntax,
+		`title contains /x/`:         itemfilter.ErrSyntax,
+		`title # "x"`:                itemfilter.ErrSyntax,
+	}
+
+	for expr, expected := range tests {
+		_, err := itemfilter.Parse(expr, compile)
+		assert.ErrorIs(t, err, expected, expr)
+	}
+}
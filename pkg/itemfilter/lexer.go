@@ -0,0 +1,228 @@
+package itemfilter
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenRegexp
+	tokenLParen
+	tokenRParen
+	tokenLBracket
+	tokenRBracket
+	tokenComma
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenEqual
+	tokenNotEqual
+	tokenMatch
+	tokenNotMatch
+	tokenContains
+	tokenIn
+)
+
+type token struct {
+	value string
+	kind  tokenKind
+	pos   int
+}
+
+func (t token) String() string {
+	if t.kind == tokenEOF {
+		return "end of expression"
+	}
+
+	return fmt.Sprintf("%q at %d", t.value, t.pos)
+}
+
+func lex(expr string) ([]token, error) {
+	runes := []rune(expr)
+	tokens := make([]token, 0)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+
+			continue
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenLParen, value: "(", pos: i})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenRParen, value: ")", pos: i})
+			i++
+		case r == '[':
+			tokens = append(tokens, token{kind: tokenLBracket, value: "[", pos: i})
+			i++
+		case r == ']':
+			tokens = append(tokens, token{kind: tokenRBracket, value: "]", pos: i})
+			i++
+		case r == ',':
+			tokens = append(tokens, token{kind: tokenComma, value: ",", pos: i})
+			i++
+		case r == '~':
+			tokens = append(tokens, token{kind: tokenMatch, value: "~", pos: i})
+			i++
+		case r == '=' && peek(runes, i+1) == '=':
+			tokens = append(tokens, token{kind: tokenEqual, value: "==", pos: i})
+			i += 2
+		case r == '!' && peek(runes, i+1) == '=':
+			tokens = append(tokens, token{kind: tokenNotEqual, value: "!=", pos: i})
+			i += 2
+		case r == '!' && peek(runes, i+1) == '~':
+			tokens = append(tokens, token{kind: tokenNotMatch, value: "!~", pos: i})
+			i += 2
+		case r == '!':
+			tokens = append(tokens, token{kind: tokenNot, value: "!", pos: i})
+			i++
+		case r == '&' && peek(runes, i+1) == '&':
+			tokens = append(tokens, token{kind: tokenAnd, value: "&&", pos: i})
+			i += 2
+		case r == '|' && peek(runes, i+1) == '|':
+			tokens = append(tokens, token{kind: tokenOr, value: "||", pos: i})
+			i += 2
+		case r == '"' || r == '\'':
+			tok, next, err := lexString(runes, i)
+			if err != nil {
+				return nil, err
+			}
+
+			tokens = append(tokens, tok)
+			i = next
+		case r == '/':
+			tok, next, err := lexRegexp(runes, i)
+			if err != nil {
+				return nil, err
+			}
+
+			tokens = append(tokens, tok)
+			i = next
+		case isIdentRune(r):
+			start := i
+
+			for i < len(runes) && isIdentRune(runes[i]) {
+				i++
+			}
+
+			tokens = append(tokens, keyword(string(runes[start:i]), start))
+		default:
+			return nil, fmt.Errorf("%w: unexpected character %q at %d", ErrSyntax, r, i)
+		}
+	}
+
+	tokens = append(tokens, token{kind: tokenEOF, pos: len(runes)})
+
+	return tokens, nil
+}
+
+func peek(runes []rune, i int) rune {
+	if i < len(runes) {
+		return runes[i]
+	}
+
+	return 0
+}
+
+func isIdentRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func keyword(word string, pos int) token {
+	switch strings.ToLower(word) {
+	case "and":
+		return token{kind: tokenAnd, value: word, pos: pos}
+	case "or":
+		return token{kind: tokenOr, value: word, pos: pos}
+	case "not":
+		return token{kind: tokenNot, value: word, pos: pos}
+	case "contains":
+		return token{kind: tokenContains, value: word, pos: pos}
+	case "in":
+		return token{kind: tokenIn, value: word, pos: pos}
+	}
+
+	return token{kind: tokenIdent, value: word, pos: pos}
+}
+
+// lexString reads quoted string, backslash escapes next character.
+func lexString(runes []rune, start int) (token, int, error) {
+	var buf strings.Builder
+
+	quote := runes[start]
+
+	for i := start + 1; i < len(runes); i++ {
+		switch runes[i] {
+		case '\\':
+			if i+1 >= len(runes) {
+				return token{}, 0, fmt.Errorf("%w: unterminated string at %d", ErrSyntax, start)
+			}
+
+			i++
+
+			_, _ = buf.WriteRune(runes[i])
+		case quote:
+			return token{kind: tokenString, value: buf.String(), pos: start}, i + 1, nil
+		default:
+			_, _ = buf.WriteRune(runes[i])
+		}
+	}
+
+	return token{}, 0, fmt.Errorf("%w: unterminated string at %d", ErrSyntax, start)
+}
+
+// lexRegexp reads `/expr/flags` literal, only `\/` is unescaped,
+// all other escapes are passed to regexp compiler as is.
+func lexRegexp(runes []rune, start int) (token, int, error) {
+	var buf strings.Builder
+
+	for i := start + 1; i < len(runes); i++ {
+		switch runes[i] {
+		case '\\':
+			if peek(runes, i+1) == '/' {
+				_ = buf.WriteByte('/')
+				i++
+
+				continue
+			}
+
+			_, _ = buf.WriteRune(runes[i])
+		case '/':
+			i++
+
+			var flags string
+
+			for i < len(runes) && unicode.IsLetter(runes[i]) {
+				flags += string(runes[i])
+				i++
+			}
+
+			expr := buf.String()
+
+			if flags != "" {
+				for _, f := range flags {
+					if !strings.ContainsRune("imsU", f) {
+						return token{}, 0, fmt.Errorf("%w: unknown regexp flag %q at %d", ErrSyntax, f, start)
+					}
+				}
+
+				expr = "(?" + flags + ")" + expr
+			}
+
+			return token{kind: tokenRegexp, value: expr, pos: start}, i, nil
+		default:
+			_, _ = buf.WriteRune(runes[i])
+		}
+	}
+
+	return token{}, 0, fmt.Errorf("%w: unterminated regexp at %d", ErrSyntax, start)
+}
@@ -0,0 +1,214 @@
+package itemfilter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Grammar:
+//
+//	expr      = and { ( "OR" | "||" ) and }
+//	and       = unary { ( "AND" | "&&" ) unary }
+//	unary     = ( "NOT" | "!" ) unary | "(" expr ")" | predicate
+//	predicate = field ( "~" | "!~" ) ( regexp | string )
+//	          | field ( "==" | "!=" | "contains" ) string
+//	          | field "in" "[" string { "," string } "]"
+type parser struct {
+	compile func(expr string) (*regexp.Regexp, error)
+	tokens  []token
+	pos     int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+
+	return tok
+}
+
+func (p *parser) expect(kind tokenKind, what string) (token, error) {
+	tok := p.next()
+	if tok.kind != kind {
+		return tok, fmt.Errorf("%w: expected %s, got %s", ErrSyntax, what, tok)
+	}
+
+	return tok, nil
+}
+
+func (p *parser) parse() (Expr, error) {
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, fmt.Errorf("%w: unexpected %s", ErrSyntax, tok)
+	}
+
+	return expr, nil
+}
+
+func (p *parser) parseOr() (Expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().kind == tokenOr {
+		_ = p.next()
+
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+
+		left = &orExpr{left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().kind == tokenAnd {
+		_ = p.next()
+
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		left = &andExpr{left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseUnary() (Expr, error) {
+	switch p.peek().kind {
+	case tokenNot:
+		_ = p.next()
+
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		return &notExpr{expr: expr}, nil
+	case tokenLParen:
+		_ = p.next()
+
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err = p.expect(tokenRParen, "')'"); err != nil {
+			return nil, err
+		}
+
+		return expr, nil
+	default:
+		return p.parsePredicate()
+	}
+}
+
+func (p *parser) parsePredicate() (Expr, error) {
+	tok, err := p.expect(tokenIdent, "field name")
+	if err != nil {
+		return nil, err
+	}
+
+	field := strings.ToLower(tok.value)
+	if !isKnownField(field) {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownField, tok)
+	}
+
+	op := p.next()
+
+	switch op.kind {
+	case tokenMatch, tokenNotMatch:
+		operand := p.next()
+		if operand.kind != tokenRegexp && operand.kind != tokenString {
+			return nil, fmt.Errorf("%w: expected regexp, got %s", ErrSyntax, operand)
+		}
+
+		re, err := p.compile(operand.value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid regexp %s: %v", ErrSyntax, operand, err)
+		}
+
+		if op.kind == tokenNotMatch {
+			return &notExpr{expr: newRegexpPredicate(field, re)}, nil
+		}
+
+		return newRegexpPredicate(field, re), nil
+	case tokenEqual, tokenNotEqual:
+		operand, err := p.expect(tokenString, "string")
+		if err != nil {
+			return nil, err
+		}
+
+		if op.kind == tokenNotEqual {
+			return &notExpr{expr: newEqualPredicate(field, operand.value)}, nil
+		}
+
+		return newEqualPredicate(field, operand.value), nil
+	case tokenContains:
+		operand, err := p.expect(tokenString, "string")
+		if err != nil {
+			return nil, err
+		}
+
+		return newContainsPredicate(field, operand.value), nil
+	case tokenIn:
+		list, err := p.parseList()
+		if err != nil {
+			return nil, err
+		}
+
+		return newInPredicate(field, list), nil
+	default:
+		return nil, fmt.Errorf("%w: expected operator, got %s", ErrSyntax, op)
+	}
+}
+
+func (p *parser) parseList() ([]string, error) {
+	if _, err := p.expect(tokenLBracket, "'['"); err != nil {
+		return nil, err
+	}
+
+	list := make([]string, 0)
+
+	for {
+		tok, err := p.expect(tokenString, "string")
+		if err != nil {
+			return nil, err
+		}
+
+		list = append(list, tok.value)
+
+		tok = p.next()
+
+		switch tok.kind {
+		case tokenComma:
+			continue
+		case tokenRBracket:
+			return list, nil
+		default:
+			return nil, fmt.Errorf("%w: expected ',' or ']', got %s", ErrSyntax, tok)
+		}
+	}
+}
@@ -0,0 +1,10 @@
+package jsonmapper
+
+import (
+	"errors"
+)
+
+var (
+	ErrSyntax  = errors.New("path syntax error")
+	ErrNoItems = errors.New("no items found")
+)
@@ -0,0 +1,117 @@
+package jsonmapper
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/araddon/dateparse"
+)
+
+// Mapping describes JSON document structure with path expressions,
+// all paths except `Items` are relative to item.
+type Mapping struct {
+	Items string `form:"items" json:"items" binding:"required,jsonpath"`
+	Title string `form:"title" json:"title" binding:"required,jsonpath"`
+	Link  string `form:"link" json:"link" binding:"omitempty,jsonpath"`
+	Date  string `form:"date" json:"date" binding:"omitempty,jsonpath"`
+	Body  string `form:"body" json:"body" binding:"omitempty,jsonpath"`
+	ID    string `form:"id" json:"id" binding:"omitempty,jsonpath"`
+}
+
+type Item struct {
+	Date time.Time
+
+	ID    string
+	Title string
+	Body  string
+	Link  string
+}
+
+// toString converts scalar JSON value to string, objects and arrays are not converted.
+func toString(val any) string {
+	switch v := val.(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	}
+
+	return ""
+}
+
+// getString returns first non-empty scalar matched by path, nil path matches nothing.
+func getString(node any, p *Path) string {
+	if p == nil {
+		return ""
+	}
+
+	for _, val := range p.Find(node) {
+		if s := strings.TrimSpace(toString(val)); s != "" {
+			return s
+		}
+	}
+
+	return ""
+}
+
+// Parse maps decoded JSON document (numbers are expected as `json.Number`
+// or `float64`) to items, items without title are skipped. When items path
+// matches single array its elements are used as items.
+func Parse(doc any, m *Mapping) ([]*Item, error) {
+	paths := make(map[string]*Path)
+
+	for _, expr := range []string{m.Items, m.Title, m.Link, m.Date, m.Body, m.ID} {
+		if expr == "" {
+			continue
+		}
+
+		p, err := CompilePath(expr)
+		if err != nil {
+			return nil, err
+		}
+
+		paths[expr] = p
+	}
+
+	nodes := paths[m.Items].Find(doc)
+	if len(nodes) == 1 {
+		if arr, ok := nodes[0].([]any); ok {
+			nodes = arr
+		}
+	}
+
+	items := make([]*Item, 0, len(nodes))
+
+	for _, node := range nodes {
+		item := &Item{
+			ID:    getString(node, paths[m.ID]),
+			Title: getString(node, paths[m.Title]),
+			Body:  getString(node, paths[m.Body]),
+			Link:  getString(node, paths[m.Link]),
+		}
+
+		if item.Title == "" {
+			continue
+		}
+
+		if val := getString(node, paths[m.Date]); val != "" {
+			if tt, err := dateparse.ParseAny(val); err == nil {
+				item.Date = tt.UTC().Round(time.Second)
+			}
+		}
+
+		items = append(items, item)
+	}
+
+	if len(items) == 0 {
+		return nil, ErrNoItems
+	}
+
+	return items, nil
+}
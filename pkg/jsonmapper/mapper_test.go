@@ -0,0 +1,91 @@
+package jsonmapper_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/s3rj1k/yafp/pkg/jsonmapper"
+	"github.com/stretchr/testify/assert"
+)
+
+const doc = `{
+  "data": {
+    "posts": [
+      {"id": 1, "attributes": {"title": "First", "url": "https://example.com/1", "created": 1651399200}, "html": "<p>One</p>"},
+      {"id": 2, "attributes": {"title": "Second", "url": "https://example.com/2", "created": "2022-05-02T10:00:00Z"}},
+      {"id": 3, "attributes": {"title": ""}}
+    ]
+  }
+}`
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	var v any
+
+	dec := json.NewDecoder(strings.NewReader(doc))
+	dec.UseNumber()
+
+	if !assert.NoError(t, dec.Decode(&v)) {
+		return
+	}
+
+	for _, items := range []string{"$.data.posts", "$.data.posts[*]", "data['posts'][*]"} {
+		out, err := jsonmapper.Parse(v, &jsonmapper.Mapping{
+			Items: items,
+			Title: "attributes.title",
+			Link:  "$.attributes.url",
+			Date:  "@.attributes.created",
+			Body:  "html",
+			ID:    "id",
+		})
+		if !assert.NoError(t, err, items) || !assert.Len(t, out, 2, items) {
+			continue
+		}
+
+		assert.Equal(t, "1", out[0].ID)
+		assert.Equal(t, "First", out[0].Title)
+		assert.Equal(t, "https://example.com/1", out[0].Link)
+		assert.Equal(t, "<p>One</p>", out[0].Body)
+		assert.Equal(t, int64(1651399200), out[0].Date.Unix())
+		assert.Equal(t, 2022, out[1].Date.Year())
+	}
+
+	_, err := jsonmapper.Parse(v, &jsonmapper.Mapping{Items: "$.data.users[*]", Title: "name"})
+	assert.ErrorIs(t, err, jsonmapper.ErrNoItems)
+}
+
+func TestCompilePath(t *testing.T) {
+	t.Parallel()
+
+	for _, expr := range []string{"$", "$.a.b", "a[0].b", "$['a b'][*]", "$.a[-1]", "$.*"} {
+		_, err := jsonmapper.CompilePath(expr)
+		assert.NoError(t, err, expr)
+	}
+
+	for _, expr := range []string{"$..a", "$.a[", "$.a[x]", "$a", "$.a."} {
+		_, err := jsonmapper.CompilePath(expr)
+		assert.ErrorIs(t, err, jsonmapper.ErrSyntax, expr)
+	}
+}
+
+func TestFindWildcard(t *testing.T) {
+	t.Parallel()
+
+	var v any
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"c": 3, "a": 1, "b": {"y": 5, "x": 4}}`), &v))
+
+	p, err := jsonmapper.CompilePath("$.*")
+	assert.NoError(t, err)
+
+	// object values are matched in keys order
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, []any{1.0, map[string]any{"x": 4.0, "y": 5.0}, 3.0}, p.Find(v))
+	}
+
+	p, err = jsonmapper.CompilePath("$.b.*")
+	assert.NoError(t, err)
+	assert.Equal(t, []any{4.0, 5.0}, p.Find(v))
+}
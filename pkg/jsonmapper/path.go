@@ -0,0 +1,174 @@
+package jsonmapper
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type stepKind int
+
+const (
+	stepKey stepKind = iota
+	stepIndex
+	stepWildcard
+)
+
+type step struct {
+	key   string
+	index int
+	kind  stepKind
+}
+
+// Path is compiled JSONPath-like expression, supported syntax is a subset of JSONPath:
+// `$` (or `@`) root, `.key`, `['key']`, `[0]`, `[-1]`, `[*]` and `.*`, root may be omitted.
+type Path struct {
+	expr  string
+	steps []step
+}
+
+func (p *Path) String() string {
+	return p.expr
+}
+
+func parseBracket(expr string, pos int) (step, int, error) {
+	end := strings.IndexByte(expr[pos:], ']')
+	if end < 0 {
+		return step{}, 0, fmt.Errorf("%w: unterminated bracket at %d", ErrSyntax, pos)
+	}
+
+	val := strings.TrimSpace(expr[pos+1 : pos+end])
+	next := pos + end + 1
+
+	switch {
+	case val == "*":
+		return step{kind: stepWildcard}, next, nil
+	case len(val) >= 2 && (val[0] == '\'' || val[0] == '"') && val[len(val)-1] == val[0]:
+		return step{kind: stepKey, key: val[1 : len(val)-1]}, next, nil
+	}
+
+	index, err := strconv.Atoi(val)
+	if err != nil {
+		return step{}, 0, fmt.Errorf("%w: invalid index %q", ErrSyntax, val)
+	}
+
+	return step{kind: stepIndex, index: index}, next, nil
+}
+
+// CompilePath parses path expression.
+func CompilePath(expr string) (*Path, error) {
+	p := &Path{
+		expr:  expr,
+		steps: make([]step, 0),
+	}
+
+	s := strings.TrimSpace(expr)
+
+	switch {
+	case strings.HasPrefix(s, "$"), strings.HasPrefix(s, "@"):
+		s = s[1:]
+	case s != "" && s[0] != '.' && s[0] != '[':
+		s = "." + s
+	}
+
+	for pos := 0; pos < len(s); {
+		switch s[pos] {
+		case '.':
+			end := pos + 1
+			for end < len(s) && s[end] != '.' && s[end] != '[' {
+				end++
+			}
+
+			key := s[pos+1 : end]
+
+			switch key {
+			case "":
+				return nil, fmt.Errorf("%w: empty key at %d in %q", ErrSyntax, pos, expr)
+			case "*":
+				p.steps = append(p.steps, step{kind: stepWildcard})
+			default:
+				p.steps = append(p.steps, step{kind: stepKey, key: key})
+			}
+
+			pos = end
+		case '[':
+			st, next, err := parseBracket(s, pos)
+			if err != nil {
+				return nil, fmt.Errorf("%w in %q", err, expr)
+			}
+
+			p.steps = append(p.steps, st)
+			pos = next
+		default:
+			return nil, fmt.Errorf("%w: unexpected %q at %d in %q", ErrSyntax, s[pos], pos, expr)
+		}
+	}
+
+	return p, nil
+}
+
+func apply(st step, node any) []any {
+	switch st.kind {
+	case stepKey:
+		if obj, ok := node.(map[string]any); ok {
+			if val, ok := obj[st.key]; ok {
+				return []any{val}
+			}
+		}
+	case stepIndex:
+		if arr, ok := node.([]any); ok {
+			index := st.index
+			if index < 0 {
+				index += len(arr)
+			}
+
+			if index >= 0 && index < len(arr) {
+				return []any{arr[index]}
+			}
+		}
+	case stepWildcard:
+		switch val := node.(type) {
+		case []any:
+			return val
+		case map[string]any:
+			// decoded object keeps no keys order, values are returned in keys order
+			keys := make([]string, 0, len(val))
+			for key := range val {
+				keys = append(keys, key)
+			}
+
+			sort.Strings(keys)
+
+			out := make([]any, 0, len(val))
+			for _, key := range keys {
+				out = append(out, val[key])
+			}
+
+			return out
+		}
+	}
+
+	return nil
+}
+
+// Find returns all values matched by path in decoded JSON document, nil path matches nothing.
+func (p *Path) Find(doc any) []any {
+	if p == nil {
+		return nil
+	}
+
+	nodes := []any{doc}
+
+	for _, st := range p.steps {
+		next := make([]any, 0, len(nodes))
+
+		for _, node := range nodes {
+			next = append(next, apply(st, node)...)
+		}
+
+		nodes = next
+	}
+
+	return nodes
+}
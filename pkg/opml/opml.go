@@ -0,0 +1,95 @@
+package opml
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+// http://opml.org/spec2.opml
+
+const (
+	ContentType = "text/x-opml; charset=utf-8"
+
+	Version = "2.0"
+
+	OutlineTypeRSS = "rss"
+)
+
+type OPML struct {
+	XMLName xml.Name `xml:"opml"`
+	Version string   `xml:"version,attr"`
+	Head    Head     `xml:"head"`
+	Body    Body     `xml:"body"`
+}
+
+type Head struct {
+	Title       string `xml:"title,omitempty"`
+	DateCreated string `xml:"dateCreated,omitempty"`
+}
+
+type Body struct {
+	Outlines []*Outline `xml:"outline"`
+}
+
+type Outline struct {
+	Text     string     `xml:"text,attr"`
+	Title    string     `xml:"title,attr,omitempty"`
+	Type     string     `xml:"type,attr,omitempty"`
+	XMLURL   string     `xml:"xmlUrl,attr,omitempty"`
+	HTMLURL  string     `xml:"htmlUrl,attr,omitempty"`
+	Outlines []*Outline `xml:"outline"`
+}
+
+// New returns empty OPML document.
+func New(title string) *OPML {
+	return &OPML{
+		Version: Version,
+		Head: Head{
+			Title: title,
+		},
+		Body: Body{
+			Outlines: make([]*Outline, 0),
+		},
+	}
+}
+
+// Parse decodes OPML document.
+func Parse(r io.Reader) (*OPML, error) {
+	v := new(OPML)
+
+	if err := xml.NewDecoder(r).Decode(v); err != nil {
+		return nil, fmt.Errorf("OPML decode error: %w", err)
+	}
+
+	return v, nil
+}
+
+// Render encodes OPML document with XML header.
+func (o *OPML) Render() (string, error) {
+	data, err := xml.MarshalIndent(o, "", "  ")
+	if err != nil {
+		return "", err //nolint:wrapcheck // pass encoding/xml error unwrapped
+	}
+
+	return xml.Header + string(data), nil
+}
+
+// Walk calls function for every feed outline (outline with `xmlUrl`), nested outlines included.
+func (o *OPML) Walk(fn func(el *Outline)) {
+	walk(o.Body.Outlines, fn)
+}
+
+func walk(outlines []*Outline, fn func(el *Outline)) {
+	for _, el := range outlines {
+		if el == nil {
+			continue
+		}
+
+		if el.XMLURL != "" {
+			fn(el)
+		}
+
+		walk(el.Outlines, fn)
+	}
+}
@@ -0,0 +1,46 @@
+package opml_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/s3rj1k/yafp/pkg/opml"
+	"github.com/stretchr/testify/assert"
+)
+
+const doc = `<?xml version="1.0" encoding="UTF-8"?>
+<opml version="1.0">
+  <head><title>Feedly</title></head>
+  <body>
+    <outline text="Movies" title="Movies">
+      <outline type="rss" text="Rutor" title="Rutor" xmlUrl="http://rutor.info/rss.php?full=10" htmlUrl="http://rutor.info/"/>
+    </outline>
+    <outline type="rss" text="Blog" xmlUrl="https://blog.example.com/feed"/>
+  </body>
+</opml>`
+
+func TestParseWalk(t *testing.T) {
+	t.Parallel()
+
+	v, err := opml.Parse(strings.NewReader(doc))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	urls := make([]string, 0)
+
+	v.Walk(func(el *opml.Outline) {
+		urls = append(urls, el.XMLURL)
+		el.XMLURL = "https://proxy/?u=" + el.XMLURL
+	})
+
+	assert.Equal(t, []string{"http://rutor.info/rss.php?full=10", "https://blog.example.com/feed"}, urls)
+
+	out, err := v.Render()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Contains(t, out, `<outline text="Movies" title="Movies">`)
+	assert.Contains(t, out, `xmlUrl="https://proxy/?u=http://rutor.info/rss.php?full=10"`)
+}
@@ -0,0 +1,7 @@
+package readability
+
+import (
+	"errors"
+)
+
+var ErrNotFound = errors.New("main content not found")
@@ -0,0 +1,167 @@
+package readability
+
+import (
+	"math"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+const (
+	minParagraphLength = 25
+	maxLengthBonus     = 3
+	charsPerBonusPoint = 100
+)
+
+var (
+	positiveRegExp = regexp.MustCompile(`(?i)article|body|content|entry|hentry|main|page|post|text|blog|story`)
+	negativeRegExp = regexp.MustCompile(`(?i)comment|meta|footer|footnote|sidebar|sponsor|promo|share|social|related|widget|banner|nav|menu|combx|masthead|popup|ad-`)
+)
+
+// removed before scoring, never part of extracted content
+const unlikelySelector = "script, style, noscript, iframe, form, nav, header, footer, aside, svg, button, input, select, textarea"
+
+func classWeight(selection *goquery.Selection) float64 {
+	var weight float64
+
+	for _, attr := range []string{"class", "id"} {
+		val, ok := selection.Attr(attr)
+		if !ok || val == "" {
+			continue
+		}
+
+		if negativeRegExp.MatchString(val) {
+			weight -= 25
+		}
+
+		if positiveRegExp.MatchString(val) {
+			weight += 25
+		}
+	}
+
+	return weight
+}
+
+func tagWeight(node *html.Node) float64 {
+	switch node.Data {
+	case "article":
+		return 10
+	case "div", "section", "main":
+		return 5
+	case "pre", "td", "blockquote":
+		return 3
+	case "ol", "ul", "dl", "dd", "dt", "li":
+		return -3
+	case "h1", "h2", "h3", "h4", "h5", "h6", "th":
+		return -5
+	}
+
+	return 0
+}
+
+func linkDensity(selection *goquery.Selection) float64 {
+	textLength := len(strings.TrimSpace(selection.Text()))
+	if textLength == 0 {
+		return 0
+	}
+
+	var linkLength int
+
+	selection.Find("a").Each(func(_ int, a *goquery.Selection) {
+		linkLength += len(strings.TrimSpace(a.Text()))
+	})
+
+	return float64(linkLength) / float64(textLength)
+}
+
+// Extract finds main content of HTML document and returns it as HTML,
+// relative links and images are resolved against base URL (when defined).
+func Extract(doc *goquery.Document, base *url.URL) (string, error) {
+	doc.Find(unlikelySelector).Remove()
+
+	scores := make(map[*html.Node]float64)
+	candidates := make([]*goquery.Selection, 0)
+
+	addScore := func(selection *goquery.Selection, score float64) {
+		if selection.Length() == 0 {
+			return
+		}
+
+		node := selection.Get(0)
+
+		if _, ok := scores[node]; !ok {
+			scores[node] = tagWeight(node) + classWeight(selection)
+			candidates = append(candidates, selection)
+		}
+
+		scores[node] += score
+	}
+
+	doc.Find("p, pre, td").Each(func(_ int, selection *goquery.Selection) {
+		text := strings.TrimSpace(selection.Text())
+		if len([]rune(text)) < minParagraphLength {
+			return
+		}
+
+		score := 1 + float64(strings.Count(text, ",")) +
+			math.Min(float64(len([]rune(text))/charsPerBonusPoint), maxLengthBonus)
+
+		addScore(selection.Parent(), score)
+		addScore(selection.Parent().Parent(), score/2) //nolint:gomnd // grandparent gets half of a score
+	})
+
+	var (
+		top      *goquery.Selection
+		topScore float64
+	)
+
+	for _, el := range candidates {
+		score := scores[el.Get(0)] * (1 - linkDensity(el))
+		if top == nil || score > topScore {
+			top, topScore = el, score
+		}
+	}
+
+	if top == nil {
+		return "", ErrNotFound
+	}
+
+	if base != nil {
+		resolveURLs(top, base)
+	}
+
+	out, err := top.Html()
+	if err != nil {
+		return "", ErrNotFound
+	}
+
+	out = strings.TrimSpace(out)
+	if out == "" {
+		return "", ErrNotFound
+	}
+
+	return out, nil
+}
+
+func resolveURLs(selection *goquery.Selection, base *url.URL) {
+	for _, el := range [][2]string{{"a", "href"}, {"img", "src"}} {
+		tag, attr := el[0], el[1]
+
+		selection.Find(tag).Each(func(_ int, s *goquery.Selection) {
+			val, ok := s.Attr(attr)
+			if !ok || val == "" {
+				return
+			}
+
+			u, err := base.Parse(val)
+			if err != nil {
+				return
+			}
+
+			s.SetAttr(attr, u.String())
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package readability_test
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/s3rj1k/yafp/pkg/readability"
+	"github.com/stretchr/testify/assert"
+)
+
+const page = `<html><body>
+<nav><a href="/">Home</a></nav>
+<div class="sidebar"><p>Related links block that is long enough to be scored as a paragraph.</p></div>
+<div class="post-content">
+<p>First paragraph of the article, with commas, many commas, indeed, enough text here.</p>
+<p>Second paragraph of the article with <a href="/more">relative link</a> and enough text.</p>
+<script>alert(1)</script>
+</div>
+<footer><p>Copyright notice that is long enough to be scored as a paragraph.</p></footer>
+</body></html>`
+
+func TestExtract(t *testing.T) {
+	t.Parallel()
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(page))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	base, _ := url.Parse("https://example.com/post/1")
+
+	out, err := readability.Extract(doc, base)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Contains(t, out, "First paragraph")
+	assert.Contains(t, out, `href="https://example.com/more"`)
+	assert.NotContains(t, out, "Related links")
+	assert.NotContains(t, out, "Copyright")
+	assert.NotContains(t, out, "<script>")
+}
+
+func TestExtractNotFound(t *testing.T) {
+	t.Parallel()
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(`<html><body><p>short</p></body></html>`))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	_, err = readability.Extract(doc, nil)
+	assert.ErrorIs(t, err, readability.ErrNotFound)
+}
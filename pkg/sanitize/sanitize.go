@@ -0,0 +1,255 @@
+package sanitize
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// maxTrackingPixelSize is the largest image width or height treated as tracking pixel.
+const maxTrackingPixelSize = 1
+
+// removed together with their content
+//
+//nolint:gochecknoglobals // read-only lookup table
+var droppedElements = map[atom.Atom]bool{
+	atom.Script:   true,
+	atom.Style:    true,
+	atom.Iframe:   true,
+	atom.Frame:    true,
+	atom.Frameset: true,
+	atom.Object:   true,
+	atom.Embed:    true,
+	atom.Applet:   true,
+	atom.Noscript: true,
+	atom.Template: true,
+	atom.Form:     true,
+	atom.Input:    true,
+	atom.Button:   true,
+	atom.Select:   true,
+	atom.Textarea: true,
+	atom.Svg:      true,
+	atom.Math:     true,
+	atom.Link:     true,
+	atom.Meta:     true,
+	atom.Base:     true,
+	atom.Head:     true,
+	atom.Title:    true,
+}
+
+// allowed elements with their allowed attributes, other elements are unwrapped
+//
+//nolint:gochecknoglobals // read-only lookup table
+var allowedElements = map[atom.Atom][]string{
+	atom.A:          {"href"},
+	atom.Abbr:       nil,
+	atom.B:          nil,
+	atom.Blockquote: {"cite"},
+	atom.Br:         nil,
+	atom.Caption:    nil,
+	atom.Cite:       nil,
+	atom.Code:       nil,
+	atom.Dd:         nil,
+	atom.Del:        nil,
+	atom.Details:    nil,
+	atom.Div:        nil,
+	atom.Dl:         nil,
+	atom.Dt:         nil,
+	atom.Em:         nil,
+	atom.Figcaption: nil,
+	atom.Figure:     nil,
+	atom.H1:         nil,
+	atom.H2:         nil,
+	atom.H3:         nil,
+	atom.H4:         nil,
+	atom.H5:         nil,
+	atom.H6:         nil,
+	atom.Hr:         nil,
+	atom.I:          nil,
+	atom.Img:        {"src", "alt", "width", "height"},
+	atom.Ins:        nil,
+	atom.Li:         nil,
+	atom.Mark:       nil,
+	atom.Ol:         {"start"},
+	atom.P:          nil,
+	atom.Pre:        nil,
+	atom.Q:          {"cite"},
+	atom.S:          nil,
+	atom.Small:      nil,
+	atom.Span:       nil,
+	atom.Strong:     nil,
+	atom.Sub:        nil,
+	atom.Summary:    nil,
+	atom.Sup:        nil,
+	atom.Table:      nil,
+	atom.Tbody:      nil,
+	atom.Td:         {"colspan", "rowspan"},
+	atom.Tfoot:      nil,
+	atom.Th:         {"colspan", "rowspan"},
+	atom.Thead:      nil,
+	atom.Tr:         nil,
+	atom.U:          nil,
+	atom.Ul:         nil,
+}
+
+// attributes that hold URLs, only safe schemes are allowed
+//
+//nolint:gochecknoglobals // read-only lookup table
+var urlAttributes = map[string]bool{
+	"href": true,
+	"src":  true,
+	"cite": true,
+}
+
+func isSafeURL(val string) bool {
+	u, err := url.Parse(strings.TrimSpace(val))
+	if err != nil {
+		return false
+	}
+
+	switch strings.ToLower(u.Scheme) {
+	case "", "http", "https", "mailto":
+		return true
+	}
+
+	return false
+}
+
+func isTrackingPixel(node *html.Node) bool {
+	for _, attr := range node.Attr {
+		if attr.Key != "width" && attr.Key != "height" {
+			continue
+		}
+
+		val, err := strconv.Atoi(strings.TrimSuffix(strings.TrimSpace(attr.Val), "px"))
+		if err == nil && val <= maxTrackingPixelSize {
+			return true
+		}
+	}
+
+	return false
+}
+
+func filterAttributes(node *html.Node, allowed []string) {
+	attrs := make([]html.Attribute, 0, len(node.Attr))
+
+	for _, attr := range node.Attr {
+		if attr.Namespace != "" {
+			continue
+		}
+
+		ok := false
+
+		for _, el := range allowed {
+			if attr.Key == el {
+				ok = true
+
+				break
+			}
+		}
+
+		if !ok {
+			continue
+		}
+
+		if urlAttributes[attr.Key] && !isSafeURL(attr.Val) {
+			continue
+		}
+
+		attrs = append(attrs, attr)
+	}
+
+	node.Attr = attrs
+}
+
+// clean sanitizes children of node in place.
+func clean(node *html.Node) {
+	for child := node.FirstChild; child != nil; {
+		next := child.NextSibling
+
+		switch child.Type {
+		case html.ElementNode:
+			allowed, ok := allowedElements[child.DataAtom]
+
+			switch {
+			case droppedElements[child.DataAtom]:
+				node.RemoveChild(child)
+			case !ok:
+				// unknown element is replaced by its sanitized content
+				clean(child)
+
+				for grandchild := child.FirstChild; grandchild != nil; grandchild = child.FirstChild {
+					child.RemoveChild(grandchild)
+					node.InsertBefore(grandchild, child)
+				}
+
+				node.RemoveChild(child)
+			default:
+				filterAttributes(child, allowed)
+
+				if child.DataAtom == atom.Img && (!hasAttr(child, "src") || isTrackingPixel(child)) {
+					node.RemoveChild(child)
+
+					break
+				}
+
+				clean(child)
+			}
+		case html.TextNode:
+		default:
+			// comments, doctype and other non-content nodes
+			node.RemoveChild(child)
+		}
+
+		child = next
+	}
+}
+
+func hasAttr(node *html.Node, key string) bool {
+	for _, attr := range node.Attr {
+		if attr.Key == key {
+			return true
+		}
+	}
+
+	return false
+}
+
+// HTML sanitizes HTML fragment to an allow-list of elements and attributes,
+// scripts, styles, frames, forms, event handlers, unsafe URLs and tracking pixels
+// are removed. Input that is not parsable is returned as escaped text.
+func HTML(in string) string {
+	if strings.TrimSpace(in) == "" {
+		return in
+	}
+
+	root := &html.Node{
+		Type:     html.ElementNode,
+		Data:     "div",
+		DataAtom: atom.Div,
+	}
+
+	nodes, err := html.ParseFragment(strings.NewReader(in), root)
+	if err != nil {
+		return html.EscapeString(in)
+	}
+
+	for _, el := range nodes {
+		root.AppendChild(el)
+	}
+
+	clean(root)
+
+	var out strings.Builder
+
+	for child := root.FirstChild; child != nil; child = child.NextSibling {
+		if err = html.Render(&out, child); err != nil {
+			return html.EscapeString(in)
+		}
+	}
+
+	return out.String()
+}
@@ -0,0 +1,34 @@
+package sanitize_test
+
+import (
+	"testing"
+
+	"github.com/s3rj1k/yafp/pkg/sanitize"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTML(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		``:                  ``,
+		`plain <b>text</b>`: `plain <b>text</b>`,
+		`<p onclick="x()" style="color:red">a</p>`:                  `<p>a</p>`,
+		`<script>alert(1)</script>b`:                                `b`,
+		`<iframe src="https://x"></iframe>c`:                        `c`,
+		`<a href="javascript:alert(1)">d</a>`:                       `<a>d</a>`,
+		`<a href="https://x/" target="_blank">e</a>`:                `<a href="https://x/">e</a>`,
+		`<img src="https://x/p.gif" width="1" height="1"/>f`:        `f`,
+		`<img src="https://x/i.png" alt="i"/>`:                      `<img src="https://x/i.png" alt="i"/>`,
+		`<img src="data:image/png;base64,AA"/>g`:                    `g`,
+		`<font color="red"><i>h</i></font>`:                         `<i>h</i>`,
+		`<!-- comment -->i<br/>`:                                    `i<br/>`,
+		`<style>p{}</style><tg-spoiler>j</tg-spoiler>`:              `j`,
+		`<svg onload="alert(1)"><script>1</script></svg>k`:          `k`,
+		`<form action="/"><input name="q"/></form>l &amp; <m>n</m>`: `l &amp; n`,
+	}
+
+	for in, expected := range tests {
+		assert.Equal(t, expected, sanitize.HTML(in), in)
+	}
+}
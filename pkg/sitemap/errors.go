@@ -0,0 +1,11 @@
+package sitemap
+
+import (
+	"errors"
+)
+
+var (
+	ErrNotFound       = errors.New("not found")
+	ErrNoData         = errors.New("no data")
+	ErrUnknownSitemap = errors.New("unknown sitemap type")
+)
@@ -0,0 +1,35 @@
+package sitemap
+
+import (
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func GetPageOpenGraphTitle(selection *goquery.Selection) (string, error) {
+	val, exists := selection.Find("meta[property='og:title']").First().Attr("content")
+	if !exists {
+		return "", ErrNotFound
+	}
+
+	val = strings.TrimSpace(val)
+	if val == "" {
+		return "", ErrNoData
+	}
+
+	return val, nil
+}
+
+func GetPageTitle(selection *goquery.Selection) (string, error) {
+	item := selection.Find("title").First()
+	if item.Length() == 0 {
+		return "", ErrNotFound
+	}
+
+	val := strings.TrimSpace(item.Text())
+	if val == "" {
+		return "", ErrNoData
+	}
+
+	return val, nil
+}
@@ -0,0 +1,128 @@
+package sitemap
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/araddon/dateparse"
+)
+
+// https://www.sitemaps.org/protocol.html
+
+// MaxUncompressedSize is protocol limit of sitemap size, compressed sitemap is decompressed up to it.
+const MaxUncompressedSize = 50 << 20
+
+type Entry struct {
+	LastMod time.Time
+
+	Loc   string
+	Title string
+}
+
+type Sitemap struct {
+	// URLs are page entries of `urlset` sitemap.
+	URLs []Entry
+	// Sitemaps are child sitemaps of sitemap index.
+	Sitemaps []Entry
+}
+
+type xmlEntry struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod"`
+	// https://developers.google.com/search/docs/crawling-indexing/sitemaps/news-sitemap
+	News struct {
+		Title           string `xml:"title"`
+		PublicationDate string `xml:"publication_date"`
+	} `xml:"news"`
+}
+
+type xmlSitemap struct {
+	XMLName  xml.Name
+	URLs     []xmlEntry `xml:"url"`
+	Sitemaps []xmlEntry `xml:"sitemap"`
+}
+
+func (el *xmlEntry) toEntry() (Entry, bool) {
+	entry := Entry{
+		Loc:   strings.TrimSpace(el.Loc),
+		Title: strings.TrimSpace(el.News.Title),
+	}
+
+	if entry.Loc == "" {
+		return entry, false
+	}
+
+	for _, val := range []string{el.LastMod, el.News.PublicationDate} {
+		if tt, err := dateparse.ParseAny(strings.TrimSpace(val)); err == nil {
+			entry.LastMod = tt.UTC().Round(time.Second)
+
+			break
+		}
+	}
+
+	return entry, true
+}
+
+// Parse parses sitemap or sitemap index, gzip compressed input is detected by magic number,
+// decompressed data larger than `MaxUncompressedSize` is not parsed.
+func Parse(r io.Reader) (*Sitemap, error) {
+	br := bufio.NewReader(r)
+
+	if magic, err := br.Peek(2); err == nil && bytes.Equal(magic, []byte{0x1f, 0x8b}) {
+		gr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("sitemap gzip error: %w", err)
+		}
+
+		defer gr.Close()
+
+		return parse(io.LimitReader(gr, MaxUncompressedSize))
+	}
+
+	return parse(br)
+}
+
+func parse(r io.Reader) (*Sitemap, error) {
+	v := new(xmlSitemap)
+
+	if err := xml.NewDecoder(r).Decode(v); err != nil {
+		return nil, fmt.Errorf("sitemap decode error: %w", err)
+	}
+
+	if v.XMLName.Local != "urlset" && v.XMLName.Local != "sitemapindex" {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownSitemap, v.XMLName.Local)
+	}
+
+	out := &Sitemap{
+		URLs:     make([]Entry, 0, len(v.URLs)),
+		Sitemaps: make([]Entry, 0, len(v.Sitemaps)),
+	}
+
+	for i := range v.URLs {
+		if entry, ok := v.URLs[i].toEntry(); ok {
+			out.URLs = append(out.URLs, entry)
+		}
+	}
+
+	for i := range v.Sitemaps {
+		if entry, ok := v.Sitemaps[i].toEntry(); ok {
+			out.Sitemaps = append(out.Sitemaps, entry)
+		}
+	}
+
+	return out, nil
+}
+
+// SortByLastMod sorts entries from the most recently modified, undated entries go last.
+func SortByLastMod(entries []Entry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].LastMod.After(entries[j].LastMod)
+	})
+}
@@ -0,0 +1,76 @@
+package sitemap_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"strings"
+	"testing"
+
+	"github.com/s3rj1k/yafp/pkg/sitemap"
+	"github.com/stretchr/testify/assert"
+)
+
+const urlset = `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9" xmlns:news="http://www.google.com/schemas/sitemap-news/0.9">
+  <url><loc>https://example.com/old</loc><lastmod>2021-01-01</lastmod></url>
+  <url><loc>https://example.com/undated</loc></url>
+  <url><loc>https://example.com/new</loc><lastmod>2022-05-01T10:00:00+00:00</lastmod></url>
+  <url><loc>https://example.com/news</loc><news:news><news:publication_date>2022-04-01</news:publication_date><news:title>News</news:title></news:news></url>
+  <url><loc> </loc></url>
+</urlset>`
+
+const index = `<?xml version="1.0" encoding="UTF-8"?>
+<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <sitemap><loc>https://example.com/sitemap-1.xml</loc><lastmod>2022-05-01</lastmod></sitemap>
+</sitemapindex>`
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	gw := gzip.NewWriter(&buf)
+	_, _ = gw.Write([]byte(urlset))
+	_ = gw.Close()
+
+	for _, r := range []*bytes.Reader{bytes.NewReader([]byte(urlset)), bytes.NewReader(buf.Bytes())} {
+		out, err := sitemap.Parse(r)
+		if !assert.NoError(t, err) || !assert.Len(t, out.URLs, 4) {
+			continue
+		}
+
+		sitemap.SortByLastMod(out.URLs)
+
+		assert.Equal(t, "https://example.com/new", out.URLs[0].Loc)
+		assert.Equal(t, "https://example.com/news", out.URLs[1].Loc)
+		assert.Equal(t, "News", out.URLs[1].Title)
+		assert.Equal(t, "https://example.com/old", out.URLs[2].Loc)
+		assert.Equal(t, "https://example.com/undated", out.URLs[3].Loc)
+	}
+
+	out, err := sitemap.Parse(strings.NewReader(index))
+	if assert.NoError(t, err) && assert.Len(t, out.Sitemaps, 1) {
+		assert.Equal(t, "https://example.com/sitemap-1.xml", out.Sitemaps[0].Loc)
+	}
+
+	_, err = sitemap.Parse(strings.NewReader(`<rss></rss>`))
+	assert.ErrorIs(t, err, sitemap.ErrUnknownSitemap)
+}
+
+func TestParseGzipLimit(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	// small compressed sitemap that is larger than protocol limit when decompressed
+	gw := gzip.NewWriter(&buf)
+	_, _ = gw.Write([]byte(`<urlset>`))
+	_, _ = gw.Write(bytes.Repeat([]byte(`<!---->`), sitemap.MaxUncompressedSize/7+1))
+	_, _ = gw.Write([]byte(`<url><loc>https://example.com/</loc></url></urlset>`))
+	_ = gw.Close()
+
+	assert.Less(t, buf.Len(), 1<<20)
+
+	_, err := sitemap.Parse(&buf)
+	assert.Error(t, err)
+}
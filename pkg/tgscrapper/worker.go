@@ -8,9 +8,13 @@ import (
 	"github.com/PuerkitoBio/goquery"
 )
 
-func Worker(ctx context.Context, data *TGMessages, userAgent string) error {
+// Worker scrapes channel pages using provided client (nil means default client),
+// prepare (when defined) customizes every request.
+func Worker(
+	ctx context.Context, client *http.Client, data *TGMessages, userAgent string, prepare func(req *http.Request),
+) error {
 	for i := 0; i < maxNumberOfPages; i++ {
-		doc, err := Get(ctx, data, userAgent)
+		doc, err := Get(ctx, client, data, userAgent, prepare)
 		if err != nil {
 			return err
 		}
@@ -35,8 +39,18 @@ func Worker(ctx context.Context, data *TGMessages, userAgent string) error {
 	return nil
 }
 
-func Get(ctx context.Context, data *TGMessages, userAgent string) (*goquery.Document, error) {
-	client := &http.Client{
+func Get(
+	ctx context.Context, client *http.Client, data *TGMessages, userAgent string, prepare func(req *http.Request),
+) (*goquery.Document, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	// copy client to not follow redirects, transport is shared
+	client = &http.Client{
+		Transport: client.Transport,
+		Jar:       client.Jar,
+		Timeout:   client.Timeout,
 		CheckRedirect: func(req *http.Request, via []*http.Request) error {
 			return http.ErrUseLastResponse
 		},
@@ -51,6 +65,10 @@ func Get(ctx context.Context, data *TGMessages, userAgent string) (*goquery.Docu
 		req.Header.Set("User-Agent", userAgent)
 	}
 
+	if prepare != nil {
+		prepare(req)
+	}
+
 	res, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("run request error: %w", err)
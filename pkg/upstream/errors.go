@@ -0,0 +1,26 @@
+package upstream
+
+import (
+	"errors"
+	"net/url"
+
+	"github.com/mmcdole/gofeed"
+)
+
+var ErrUnsupportedProxyScheme = errors.New("unsupported proxy scheme")
+
+// DocumentError is returned when upstream response is not a feed,
+// it holds response body (e.g. HTML page) for feed discovery.
+type DocumentError struct {
+	URL         *url.URL
+	ContentType string
+	Body        []byte
+}
+
+func (e *DocumentError) Error() string {
+	return gofeed.ErrFeedTypeNotDetected.Error()
+}
+
+func (e *DocumentError) Unwrap() error {
+	return gofeed.ErrFeedTypeNotDetected
+}
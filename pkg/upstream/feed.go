@@ -0,0 +1,123 @@
+package upstream
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/jellydator/ttlcache/v3"
+	"github.com/mmcdole/gofeed"
+)
+
+const (
+	DefaultKeyPrefix = "UPSTREAM:"
+)
+
+// record is last successfully parsed upstream feed with its cache validators,
+// stored feed is shared between requests and must not be modified.
+type record struct {
+	feed         *gofeed.Feed
+	etag         string
+	lastModified string
+}
+
+// ParseURL works like `gofeed.Parser.ParseURLWithContext` but remembers upstream
+// `ETag` and `Last-Modified` response headers and parsed feed per feed URL,
+// following requests are conditional and `304 Not Modified` reuses stored feed.
+// Request is customized with provided options, options may be nil,
+// response body is read up to `maxBodySize` bytes.
+func ParseURL(
+	ctx context.Context, cache *ttlcache.Cache[string, any], fp *gofeed.Parser,
+	feedURL string, opts *Options, recordTTL time.Duration, maxBodySize int64,
+) (*gofeed.Feed, error) {
+	key := fmt.Sprintf("%s%s %s", DefaultKeyPrefix, opts, feedURL)
+
+	var prev *record
+
+	if item := cache.Get(key); item != nil {
+		if val, ok := item.Value().(*record); ok {
+			prev = val
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, feedURL, http.NoBody)
+	if err != nil {
+		return nil, fmt.Errorf("prepare request error: %w", err)
+	}
+
+	req.Header.Set("User-Agent", fp.UserAgent)
+
+	opts.Prepare(req)
+
+	if prev != nil {
+		if prev.etag != "" {
+			req.Header.Set("If-None-Match", prev.etag)
+		}
+
+		if prev.lastModified != "" {
+			req.Header.Set("If-Modified-Since", prev.lastModified)
+		}
+	}
+
+	client := fp.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // pass net/http error unwrapped, same as gofeed does
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotModified && prev != nil {
+		// refresh record expiration
+		_ = cache.Set(key, prev, recordTTL)
+
+		return prev.feed, nil
+	}
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return nil, gofeed.HTTPError{
+			StatusCode: res.StatusCode,
+			Status:     res.Status,
+		}
+	}
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, maxBodySize))
+	if err != nil {
+		return nil, fmt.Errorf("read response error: %w", err)
+	}
+
+	feed, err := fp.Parse(bytes.NewReader(body))
+	if errors.Is(err, gofeed.ErrFeedTypeNotDetected) {
+		return nil, &DocumentError{
+			URL:         res.Request.URL,
+			ContentType: res.Header.Get("Content-Type"),
+			Body:        body,
+		}
+	}
+
+	if err != nil {
+		return nil, err //nolint:wrapcheck // pass gofeed error unwrapped, see `feedhlp.HTTPErrorResponse`
+	}
+
+	etag, lastModified := res.Header.Get("ETag"), res.Header.Get("Last-Modified")
+
+	if etag != "" || lastModified != "" {
+		_ = cache.Set(key, &record{
+			feed:         feed,
+			etag:         etag,
+			lastModified: lastModified,
+		}, recordTTL)
+	} else if prev != nil {
+		cache.Delete(key)
+	}
+
+	return feed, nil
+}
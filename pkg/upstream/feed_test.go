@@ -0,0 +1,191 @@
+package upstream_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jellydator/ttlcache/v3"
+	"github.com/mmcdole/gofeed"
+	"github.com/s3rj1k/yafp/pkg/upstream"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testRecordTTL   = time.Minute
+	testMaxBodySize = 1 << 20
+)
+
+func rss(title string) string {
+	return fmt.Sprintf(`<?xml version="1.0"?><rss version="2.0"><channel><title>%s</title>`+
+		`<item><title>item</title><guid>1</guid></item></channel></rss>`, title)
+}
+
+// conditionalServer serves feed with configured validators and answers
+// `304 Not Modified` to matching conditional requests.
+type conditionalServer struct {
+	mu sync.Mutex
+
+	title        string
+	etag         string
+	lastModified string
+
+	ifNoneMatch     string
+	ifModifiedSince string
+	notModified     int
+}
+
+func (s *conditionalServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.ifNoneMatch = r.Header.Get("If-None-Match")
+	s.ifModifiedSince = r.Header.Get("If-Modified-Since")
+
+	if (s.etag != "" && s.ifNoneMatch == s.etag) ||
+		(s.lastModified != "" && s.ifModifiedSince == s.lastModified) {
+		s.notModified++
+
+		w.WriteHeader(http.StatusNotModified)
+
+		return
+	}
+
+	if s.etag != "" {
+		w.Header().Set("ETag", s.etag)
+	}
+
+	if s.lastModified != "" {
+		w.Header().Set("Last-Modified", s.lastModified)
+	}
+
+	w.Header().Set("Content-Type", "application/rss+xml")
+	_, _ = w.Write([]byte(rss(s.title)))
+}
+
+func (s *conditionalServer) set(fn func(s *conditionalServer)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fn(s)
+}
+
+func (s *conditionalServer) get(fn func(s *conditionalServer)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fn(s)
+}
+
+func TestParseURLConditional(t *testing.T) {
+	t.Parallel()
+
+	srv := &conditionalServer{
+		title: "v1",
+		etag:  `"v1"`,
+	}
+
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	cache := ttlcache.New[string, any]()
+	fp := gofeed.NewParser()
+
+	parse := func() *gofeed.Feed {
+		feed, err := upstream.ParseURL(context.Background(), cache, fp, ts.URL, nil, testRecordTTL, testMaxBodySize)
+		assert.NoError(t, err)
+
+		return feed
+	}
+
+	first := parse()
+	assert.Equal(t, "v1", first.Title)
+
+	srv.get(func(s *conditionalServer) {
+		assert.Empty(t, s.ifNoneMatch)
+	})
+
+	// ETag is sent back, `304 Not Modified` reuses stored feed
+	second := parse()
+	assert.Same(t, first, second)
+
+	srv.get(func(s *conditionalServer) {
+		assert.Equal(t, `"v1"`, s.ifNoneMatch)
+		assert.Equal(t, 1, s.notModified)
+	})
+
+	// Last-Modified is sent back
+	srv.set(func(s *conditionalServer) {
+		s.title = "v2"
+		s.etag = ""
+		s.lastModified = "Mon, 01 Jan 2024 00:00:00 GMT"
+	})
+
+	assert.Equal(t, "v2", parse().Title)
+	assert.Equal(t, "v2", parse().Title)
+
+	srv.get(func(s *conditionalServer) {
+		assert.Equal(t, "Mon, 01 Jan 2024 00:00:00 GMT", s.ifModifiedSince)
+		assert.Equal(t, 2, s.notModified)
+	})
+
+	// validators are gone, stored record is dropped
+	srv.set(func(s *conditionalServer) {
+		s.title = "v3"
+		s.lastModified = ""
+	})
+
+	assert.Equal(t, "v3", parse().Title)
+	assert.Equal(t, "v3", parse().Title)
+
+	srv.get(func(s *conditionalServer) {
+		assert.Empty(t, s.ifNoneMatch)
+		assert.Empty(t, s.ifModifiedSince)
+		assert.Equal(t, 2, s.notModified)
+	})
+}
+
+func TestParseURLErrors(t *testing.T) {
+	t.Parallel()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+		case "/page":
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = w.Write([]byte("<html><head><title>page</title></head></html>"))
+		default:
+			_, _ = w.Write([]byte(rss(strings.Repeat("x", 2*testMaxBodySize))))
+		}
+	}))
+	defer ts.Close()
+
+	cache := ttlcache.New[string, any]()
+	fp := gofeed.NewParser()
+
+	_, err := upstream.ParseURL(context.Background(), cache, fp, ts.URL+"/missing", nil, testRecordTTL, testMaxBodySize)
+
+	var httpErr gofeed.HTTPError
+
+	assert.True(t, errors.As(err, &httpErr))
+	assert.Equal(t, http.StatusNotFound, httpErr.StatusCode)
+
+	_, err = upstream.ParseURL(context.Background(), cache, fp, ts.URL+"/page", nil, testRecordTTL, testMaxBodySize)
+
+	var docErr *upstream.DocumentError
+
+	assert.True(t, errors.As(err, &docErr))
+	assert.ErrorIs(t, err, gofeed.ErrFeedTypeNotDetected)
+	assert.Equal(t, "text/html", docErr.ContentType)
+
+	// body is read up to limit, truncated feed is not parsed
+	_, err = upstream.ParseURL(context.Background(), cache, fp, ts.URL+"/large", nil, testRecordTTL, testMaxBodySize)
+	assert.Error(t, err)
+}
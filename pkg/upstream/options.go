@@ -0,0 +1,121 @@
+package upstream
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// maxRedirects is the same as default limit of `http.Client`.
+const maxRedirects = 10
+
+type optionsKey struct{}
+
+// Options customizes requests to upstream hosts, credentials are sent
+// only to listed hosts so named options can not leak them to arbitrary URLs.
+type Options struct {
+	// Name identifies options in cache keys, responses depend on credentials.
+	Name string `json:"-"`
+
+	Hosts []string `json:"hosts" binding:"required,min=1,dive,required"`
+
+	Headers map[string]string `json:"headers" binding:"omitempty,dive,keys,required,endkeys"`
+	Cookies map[string]string `json:"cookies" binding:"omitempty,dive,keys,required,endkeys"`
+
+	Username    string `json:"username" binding:"required_with=Password"`
+	Password    string `json:"password"`
+	BearerToken string `json:"bearerToken" binding:"excluded_with=Username"`
+}
+
+// String returns options name, it is safe to call on nil options.
+func (o *Options) String() string {
+	if o == nil {
+		return ""
+	}
+
+	return o.Name
+}
+
+// Match reports whether options are applied to requests to provided host.
+func (o *Options) Match(host string) bool {
+	if o == nil {
+		return false
+	}
+
+	for _, el := range o.Hosts {
+		if strings.EqualFold(el, host) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Prepare sets headers, cookies and authorization on request to matching host,
+// options are kept in request context and client made by `NewClient` applies them
+// again on every redirect. It is safe to call on nil options.
+func (o *Options) Prepare(req *http.Request) {
+	if o == nil {
+		return
+	}
+
+	*req = *req.WithContext(context.WithValue(req.Context(), optionsKey{}, o))
+
+	o.apply(req)
+}
+
+func (o *Options) apply(req *http.Request) {
+	if !o.Match(req.URL.Hostname()) {
+		return
+	}
+
+	for key, val := range o.Headers {
+		req.Header.Set(key, val)
+	}
+
+	for name, val := range o.Cookies {
+		req.AddCookie(&http.Cookie{
+			Name:  name,
+			Value: val,
+		})
+	}
+
+	if o.Username != "" {
+		req.SetBasicAuth(o.Username, o.Password)
+	}
+
+	if o.BearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+o.BearerToken)
+	}
+}
+
+// strip removes headers, cookies and authorization that are set by options.
+func (o *Options) strip(req *http.Request) {
+	for key := range o.Headers {
+		req.Header.Del(key)
+	}
+
+	if len(o.Cookies) > 0 {
+		req.Header.Del("Cookie")
+	}
+
+	if o.Username != "" || o.BearerToken != "" {
+		req.Header.Del("Authorization")
+	}
+}
+
+// checkRedirect applies options of original request to redirect request, redirect
+// request carries headers of original request, they are removed when host is not listed.
+func checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxRedirects {
+		return fmt.Errorf("stopped after %d redirects", maxRedirects)
+	}
+
+	if o, ok := req.Context().Value(optionsKey{}).(*Options); ok {
+		o.strip(req)
+		o.apply(req)
+	}
+
+	return nil
+}
@@ -0,0 +1,70 @@
+package upstream
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ParseProxyURL parses proxy URL, supported schemes are `http`, `https` (HTTP CONNECT)
+// and `socks5` (host name is resolved by proxy).
+func ParseProxyURL(val string) (*url.URL, error) {
+	u, err := url.Parse(val)
+	if err != nil {
+		return nil, fmt.Errorf("proxy URL error: %w", err)
+	}
+
+	switch u.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedProxyScheme, u.Scheme)
+	}
+
+	if u.Host == "" {
+		return nil, fmt.Errorf("proxy URL error: undefined host: %q", val)
+	}
+
+	return u, nil
+}
+
+// ProxyFunc returns `http.Transport.Proxy` function, proxy is selected by request
+// host (exact host name match) first, then default proxy (may be nil) is used,
+// then proxy is taken from environment.
+func ProxyFunc(defaultProxy *url.URL, hosts map[string]*url.URL) func(req *http.Request) (*url.URL, error) {
+	byHost := make(map[string]*url.URL, len(hosts))
+
+	for host, u := range hosts {
+		byHost[strings.ToLower(host)] = u
+	}
+
+	return func(req *http.Request) (*url.URL, error) {
+		if u, ok := byHost[strings.ToLower(req.URL.Hostname())]; ok {
+			return u, nil
+		}
+
+		if defaultProxy != nil {
+			return defaultProxy, nil
+		}
+
+		return http.ProxyFromEnvironment(req)
+	}
+}
+
+// NewClient returns HTTP client that routes requests through selected proxy,
+// upstream options of request (see `Options.Prepare`) are applied on every redirect.
+func NewClient(proxy func(req *http.Request) (*url.URL, error)) *http.Client {
+	transport, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		transport = new(http.Transport)
+	} else {
+		transport = transport.Clone()
+	}
+
+	transport.Proxy = proxy
+
+	return &http.Client{
+		Transport:     transport,
+		CheckRedirect: checkRedirect,
+	}
+}
@@ -0,0 +1,11 @@
+package websub
+
+import (
+	"errors"
+)
+
+var (
+	ErrInvalidMode  = errors.New("invalid hub mode")
+	ErrVerification = errors.New("subscriber verification failed")
+	ErrLimit        = errors.New("subscription limit reached")
+)
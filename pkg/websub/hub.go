@@ -0,0 +1,384 @@
+package websub
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// https://www.w3.org/TR/websub/
+
+const (
+	ModeSubscribe   = "subscribe"
+	ModeUnsubscribe = "unsubscribe"
+
+	DefaultLease = 10 * 24 * time.Hour
+	MaxLease     = 30 * 24 * time.Hour
+
+	MaxSecretSizeBytes = 200
+
+	requestTimeout  = 30 * time.Second
+	challengeLength = 16
+)
+
+// Request is subscription request, it is expected to be validated by caller.
+type Request struct {
+	Mode     string
+	Topic    string
+	Callback string
+	Secret   string
+	Lease    time.Duration
+}
+
+type subscription struct {
+	expiresAt time.Time
+	secret    string
+	host      string
+}
+
+type topic struct {
+	subscribers map[string]*subscription
+	digest      string
+	seen        bool
+}
+
+// Hub verifies subscriptions and pushes new content of topics to subscribers,
+// topic content is fetched with `Fetch` on every `Poll`.
+type Hub struct {
+	// URL is public hub URL advertised in pushed content.
+	URL string
+
+	Fetch  func(ctx context.Context, topic string) (contentType string, body []byte, err error)
+	Client *http.Client
+
+	// Digest identifies topic content, content is pushed when digest changes,
+	// nil means that whole body is compared.
+	Digest func(body []byte) []byte
+
+	// MaxTopics, MaxSubscriptions and MaxHostSubscriptions limit number of topics,
+	// subscriptions and subscriptions per callback host, zero means no limit.
+	// Renewal of existing subscription is not limited.
+	MaxTopics            int
+	MaxSubscriptions     int
+	MaxHostSubscriptions int
+
+	topics map[string]*topic
+	mu     sync.Mutex
+}
+
+// NewHub creates hub that uses default HTTP client.
+func NewHub(hubURL string, fetch func(ctx context.Context, topic string) (string, []byte, error)) *Hub {
+	return &Hub{
+		URL:    hubURL,
+		Fetch:  fetch,
+		Client: http.DefaultClient,
+		topics: make(map[string]*topic),
+	}
+}
+
+// LinkHeader returns `Link` header value that advertises hub and topic.
+func LinkHeader(hubURL, topicURL string) string {
+	return fmt.Sprintf(`<%s>; rel="hub", <%s>; rel="self"`, hubURL, topicURL)
+}
+
+// Signature returns `X-Hub-Signature` header value for content.
+func Signature(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Subscribers returns number of active subscribers of topic.
+func (h *Hub) Subscribers(topicURL string) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if t, ok := h.topics[topicURL]; ok {
+		return len(t.subscribers)
+	}
+
+	return 0
+}
+
+// Check reports whether subscription request fits hub limits, it is checked again by `Verify`.
+func (h *Hub) Check(req *Request) error {
+	if req.Mode != ModeSubscribe {
+		return nil
+	}
+
+	u, err := url.Parse(req.Callback)
+	if err != nil {
+		return fmt.Errorf("callback URL error: %w", err)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.checkLimits(req.Topic, req.Callback, u.Hostname())
+}
+
+// checkLimits must be called with hub lock held.
+func (h *Hub) checkLimits(topicURL, callback, host string) error {
+	if t, ok := h.topics[topicURL]; ok {
+		if _, ok = t.subscribers[callback]; ok {
+			return nil
+		}
+	} else if h.MaxTopics > 0 && len(h.topics) >= h.MaxTopics {
+		return fmt.Errorf("%w: %d topics", ErrLimit, h.MaxTopics)
+	}
+
+	var total, perHost int
+
+	for _, t := range h.topics {
+		for _, sub := range t.subscribers {
+			total++
+
+			if sub.host == host {
+				perHost++
+			}
+		}
+	}
+
+	if h.MaxSubscriptions > 0 && total >= h.MaxSubscriptions {
+		return fmt.Errorf("%w: %d subscriptions", ErrLimit, h.MaxSubscriptions)
+	}
+
+	if h.MaxHostSubscriptions > 0 && perHost >= h.MaxHostSubscriptions {
+		return fmt.Errorf("%w: %d subscriptions of %q", ErrLimit, h.MaxHostSubscriptions, host)
+	}
+
+	return nil
+}
+
+// Verify confirms intent of subscriber with challenge and applies request,
+// subscription that does not fit hub limits is rejected before verification.
+func (h *Hub) Verify(ctx context.Context, req *Request) error {
+	if req.Mode != ModeSubscribe && req.Mode != ModeUnsubscribe {
+		return fmt.Errorf("%w: %q", ErrInvalidMode, req.Mode)
+	}
+
+	if err := h.Check(req); err != nil {
+		return err
+	}
+
+	lease := req.Lease
+	if lease <= 0 {
+		lease = DefaultLease
+	}
+
+	if lease > MaxLease {
+		lease = MaxLease
+	}
+
+	challenge := make([]byte, challengeLength)
+	if _, err := rand.Read(challenge); err != nil {
+		return fmt.Errorf("challenge error: %w", err)
+	}
+
+	u, err := url.Parse(req.Callback)
+	if err != nil {
+		return fmt.Errorf("callback URL error: %w", err)
+	}
+
+	query := u.Query()
+
+	query.Set("hub.mode", req.Mode)
+	query.Set("hub.topic", req.Topic)
+	query.Set("hub.challenge", hex.EncodeToString(challenge))
+
+	if req.Mode == ModeSubscribe {
+		query.Set("hub.lease_seconds", strconv.Itoa(int(lease.Seconds())))
+	}
+
+	u.RawQuery = query.Encode()
+
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	verifyReq, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), http.NoBody)
+	if err != nil {
+		return fmt.Errorf("prepare request error: %w", err)
+	}
+
+	res, err := h.Client.Do(verifyReq)
+	if err != nil {
+		return fmt.Errorf("run request error: %w", err)
+	}
+
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, int64(len(challenge)*2+1))) //nolint:gomnd // hex encoded challenge
+	if err != nil {
+		return fmt.Errorf("read response error: %w", err)
+	}
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 || string(body) != query.Get("hub.challenge") {
+		return ErrVerification
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if req.Mode == ModeUnsubscribe {
+		if t, ok := h.topics[req.Topic]; ok {
+			delete(t.subscribers, req.Callback)
+
+			if len(t.subscribers) == 0 {
+				delete(h.topics, req.Topic)
+			}
+		}
+
+		return nil
+	}
+
+	// limits are checked again, concurrent requests may be verified meanwhile
+	if err = h.checkLimits(req.Topic, req.Callback, u.Hostname()); err != nil {
+		return err
+	}
+
+	t, ok := h.topics[req.Topic]
+	if !ok {
+		t = &topic{
+			subscribers: make(map[string]*subscription),
+		}
+
+		h.topics[req.Topic] = t
+	}
+
+	t.subscribers[req.Callback] = &subscription{
+		expiresAt: time.Now().Add(lease),
+		secret:    req.Secret,
+		host:      u.Hostname(),
+	}
+
+	return nil
+}
+
+// push delivers content to subscriber, subscriber that is gone is reported with `false`.
+func (h *Hub) push(ctx context.Context, topicURL, callback string, sub *subscription, contentType string, body []byte) bool {
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, callback, bytes.NewReader(body))
+	if err != nil {
+		return false
+	}
+
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Link", LinkHeader(h.URL, topicURL))
+
+	if sub.secret != "" {
+		req.Header.Set("X-Hub-Signature", Signature(sub.secret, body))
+	}
+
+	res, err := h.Client.Do(req)
+	if err != nil {
+		return true // keep subscription, delivery is retried on next change
+	}
+
+	_ = res.Body.Close()
+
+	return res.StatusCode != http.StatusGone
+}
+
+// Poll fetches every subscribed topic once and pushes changed content to subscribers,
+// first fetch of topic only records content. Expired subscriptions are removed.
+func (h *Hub) Poll(ctx context.Context) {
+	now := time.Now()
+
+	h.mu.Lock()
+
+	topics := make([]string, 0, len(h.topics))
+
+	for topicURL, t := range h.topics {
+		for callback, sub := range t.subscribers {
+			if now.After(sub.expiresAt) {
+				delete(t.subscribers, callback)
+			}
+		}
+
+		if len(t.subscribers) == 0 {
+			delete(h.topics, topicURL)
+
+			continue
+		}
+
+		topics = append(topics, topicURL)
+	}
+
+	h.mu.Unlock()
+
+	for _, topicURL := range topics {
+		if ctx.Err() != nil {
+			return
+		}
+
+		contentType, body, err := h.Fetch(ctx, topicURL)
+		if err != nil {
+			continue
+		}
+
+		digest := h.digest(body)
+		subscribers := make(map[string]*subscription)
+
+		h.mu.Lock()
+
+		if t, ok := h.topics[topicURL]; ok {
+			if t.seen && t.digest != digest {
+				for callback, sub := range t.subscribers {
+					subscribers[callback] = sub
+				}
+			}
+
+			t.digest, t.seen = digest, true
+		}
+
+		h.mu.Unlock()
+
+		for callback, sub := range subscribers {
+			if !h.push(ctx, topicURL, callback, sub, contentType, body) {
+				h.mu.Lock()
+				if t, ok := h.topics[topicURL]; ok {
+					delete(t.subscribers, callback)
+				}
+				h.mu.Unlock()
+			}
+		}
+	}
+}
+
+func (h *Hub) digest(body []byte) string {
+	if h.Digest != nil {
+		return string(h.Digest(body))
+	}
+
+	sum := sha256.Sum256(body)
+
+	return string(sum[:])
+}
+
+// Run polls topics with provided interval until context is canceled.
+func (h *Hub) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			h.Poll(ctx)
+		}
+	}
+}
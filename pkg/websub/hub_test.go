@@ -0,0 +1,165 @@
+package websub_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/s3rj1k/yafp/pkg/websub"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	hubURL   = "https://yafp.example.com/websub"
+	topicURL = "https://yafp.example.com/tg/channel"
+	secret   = "secret"
+)
+
+type subscriber struct {
+	pushes []*http.Request
+	bodies []string
+	mu     sync.Mutex
+}
+
+func (s *subscriber) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodGet {
+		if r.URL.Query().Get("hub.topic") != topicURL {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		_, _ = io.WriteString(w, r.URL.Query().Get("hub.challenge"))
+
+		return
+	}
+
+	body, _ := io.ReadAll(r.Body)
+
+	s.mu.Lock()
+	s.pushes = append(s.pushes, r)
+	s.bodies = append(s.bodies, string(body))
+	s.mu.Unlock()
+}
+
+func TestHub(t *testing.T) {
+	t.Parallel()
+
+	sub := new(subscriber)
+
+	srv := httptest.NewServer(sub)
+	defer srv.Close()
+
+	content := "v1"
+
+	hub := websub.NewHub(hubURL, func(_ context.Context, _ string) (string, []byte, error) {
+		return "application/rss+xml", []byte(content), nil
+	})
+
+	ctx := context.Background()
+
+	err := hub.Verify(ctx, &websub.Request{
+		Mode:     websub.ModeSubscribe,
+		Topic:    "https://yafp.example.com/other",
+		Callback: srv.URL,
+	})
+	assert.ErrorIs(t, err, websub.ErrVerification)
+
+	err = hub.Verify(ctx, &websub.Request{
+		Mode:     websub.ModeSubscribe,
+		Topic:    topicURL,
+		Callback: srv.URL + "/callback?id=1",
+		Secret:   secret,
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, 1, hub.Subscribers(topicURL))
+
+	hub.Poll(ctx) // records initial content
+	hub.Poll(ctx) // unchanged content
+
+	content = "v2"
+
+	hub.Poll(ctx)
+
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+
+	if !assert.Len(t, sub.pushes, 1) {
+		return
+	}
+
+	assert.Equal(t, "v2", sub.bodies[0])
+	assert.Equal(t, "1", sub.pushes[0].URL.Query().Get("id"))
+	assert.Equal(t, "application/rss+xml", sub.pushes[0].Header.Get("Content-Type"))
+	assert.Equal(t, websub.LinkHeader(hubURL, topicURL), sub.pushes[0].Header.Get("Link"))
+	assert.Equal(t, websub.Signature(secret, []byte("v2")), sub.pushes[0].Header.Get("X-Hub-Signature"))
+}
+
+func TestHubLimits(t *testing.T) {
+	t.Parallel()
+
+	// subscriber confirms every request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, r.URL.Query().Get("hub.challenge"))
+	}))
+	defer srv.Close()
+
+	hub := websub.NewHub(hubURL, func(_ context.Context, _ string) (string, []byte, error) {
+		return "application/rss+xml", nil, nil
+	})
+
+	hub.MaxTopics = 2
+	hub.MaxSubscriptions = 3
+	hub.MaxHostSubscriptions = 2
+
+	ctx := context.Background()
+
+	subscribe := func(topic, callback string) error {
+		return hub.Verify(ctx, &websub.Request{
+			Mode:     websub.ModeSubscribe,
+			Topic:    topic,
+			Callback: callback,
+		})
+	}
+
+	// callback host differs from server host, but resolves to it
+	otherHost := strings.Replace(srv.URL, "127.0.0.1", "localhost", 1)
+
+	assert.NoError(t, subscribe(topicURL+"1", srv.URL+"/1"))
+	assert.NoError(t, subscribe(topicURL+"1", srv.URL+"/2"))
+
+	// renewal is not limited
+	assert.NoError(t, subscribe(topicURL+"1", srv.URL+"/2"))
+
+	// per host limit
+	assert.ErrorIs(t, subscribe(topicURL+"2", srv.URL+"/3"), websub.ErrLimit)
+	assert.NoError(t, subscribe(topicURL+"2", otherHost+"/1"))
+
+	// topics limit
+	assert.ErrorIs(t, hub.Check(&websub.Request{
+		Mode:     websub.ModeSubscribe,
+		Topic:    topicURL + "3",
+		Callback: otherHost + "/2",
+	}), websub.ErrLimit)
+
+	// subscriptions limit
+	assert.ErrorIs(t, subscribe(topicURL+"2", otherHost+"/2"), websub.ErrLimit)
+
+	// unsubscribe frees limits
+	assert.NoError(t, hub.Verify(ctx, &websub.Request{
+		Mode:     websub.ModeUnsubscribe,
+		Topic:    topicURL + "1",
+		Callback: srv.URL + "/1",
+	}))
+	assert.NoError(t, subscribe(topicURL+"2", srv.URL+"/3"))
+
+	assert.Equal(t, 1, hub.Subscribers(topicURL+"1"))
+	assert.Equal(t, 2, hub.Subscribers(topicURL+"2"))
+}
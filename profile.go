@@ -0,0 +1,34 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Profile struct {
+	Mute
+}
+
+type ProfileName struct {
+	Name string `uri:"name" binding:"required"`
+}
+
+func handleProfile(c *gin.Context) {
+	cfg := new(ProfileName)
+
+	if err := c.BindUri(cfg); err != nil {
+		c.String(http.StatusBadRequest, "invalid profile name\n")
+
+		return
+	}
+
+	profile, ok := config.Profiles[cfg.Name]
+	if !ok || profile == nil {
+		c.String(http.StatusNotFound, "%d Not Found\n", http.StatusNotFound)
+
+		return
+	}
+
+	serveMuteFeed(c, &profile.Mute)
+}
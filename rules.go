@@ -0,0 +1,219 @@
+package main
+
+import (
+	"html"
+	"strings"
+	"time"
+
+	"github.com/jlelse/feeds"
+	"github.com/s3rj1k/yafp/pkg/cachedregexp"
+	"github.com/s3rj1k/yafp/pkg/feedhlp"
+	"github.com/s3rj1k/yafp/pkg/itemfilter"
+)
+
+const (
+	muteModeKeep = "keep"
+
+	markAuthor      = "author"
+	markTitle       = "title"
+	markCategory    = "category"
+	markDescription = "description"
+
+	defaultMarkText = "[MUTED]"
+)
+
+// Rules defines items mute and rewrite rules.
+type Rules struct {
+	TitleQuery         string   `form:"title_query" json:"titleQuery" binding:"regexp"`
+	DescriptionQuery   string   `form:"description_query" json:"descriptionQuery" binding:"regexp"`
+	CategoryQuery      string   `form:"category_query" json:"categoryQuery" binding:"regexp"`
+	CategoryExclude    []string `form:"category_exclude" json:"categoryExclude" binding:"dive,required"`
+	Filter             string   `form:"filter" json:"filter" binding:"filter"`
+	RewriteAuthor      string   `form:"rewrite_author" json:"rewriteAuthor" binding:"ascii"`
+	Mark               string   `form:"mark" json:"mark" binding:"omitempty,oneof=author title category description"`
+	MarkText           string   `form:"mark_text" json:"markText"`
+	Mode               string   `form:"mode" json:"mode" binding:"omitempty,oneof=mute keep"`
+	TitleReplace       string   `form:"title_replace" json:"titleReplace" binding:"regexp"`
+	TitleWith          string   `form:"title_with" json:"titleWith"`
+	DescriptionReplace string   `form:"description_replace" json:"descriptionReplace" binding:"regexp"`
+	DescriptionWith    string   `form:"description_with" json:"descriptionWith"`
+	AuthorReplace      string   `form:"author_replace" json:"authorReplace" binding:"regexp"`
+	AuthorWith         string   `form:"author_with" json:"authorWith"`
+}
+
+func rulesProperURLQueryParamsName() []string {
+	return []string{
+		"TitleQuery", "title_query",
+		"DescriptionQuery", "description_query",
+		"CategoryQuery", "category_query",
+		"CategoryExclude", "category_exclude",
+		"Filter", "filter",
+		"RewriteAuthor", "rewrite_author",
+		"MarkText", "mark_text",
+		"Mark", "mark",
+		"Mode", "mode",
+		"TitleReplace", "title_replace",
+		"TitleWith", "title_with",
+		"DescriptionReplace", "description_replace",
+		"DescriptionWith", "description_with",
+		"AuthorReplace", "author_replace",
+		"AuthorWith", "author_with",
+	}
+}
+
+func (r *Rules) hasQuery() bool {
+	return r.hasMatchQuery() || len(r.CategoryExclude) > 0
+}
+
+// hasMatchQuery reports whether rules have query that is inverted by keep mode,
+// excluded categories are not inverted.
+func (r *Rules) hasMatchQuery() bool {
+	return r.TitleQuery != "" || r.DescriptionQuery != "" || r.CategoryQuery != "" || r.Filter != ""
+}
+
+func (r *Rules) hasReplace() bool {
+	return r.TitleReplace != "" || r.DescriptionReplace != "" || r.AuthorReplace != ""
+}
+
+// markMode returns how matched items are marked, `rewrite_author` is an alias
+// for author mark, empty mode means that matched items are dropped.
+func (r *Rules) markMode() (mode, text string) {
+	mode = r.Mark
+
+	if mode == "" && r.RewriteAuthor != "" {
+		mode = markAuthor
+	}
+
+	switch {
+	case r.MarkText != "":
+		text = r.MarkText
+	case r.RewriteAuthor != "":
+		text = r.RewriteAuthor
+	default:
+		text = defaultMarkText
+	}
+
+	return mode, text
+}
+
+func markItem(item *feedhlp.Item, mode, text string) {
+	switch mode {
+	case markAuthor:
+		item.Author = &feeds.Author{
+			Name:  text,
+			Email: "",
+		}
+	case markTitle:
+		item.Title = text + " " + item.Title
+	case markCategory:
+		item.Categories = append(item.Categories, text)
+	case markDescription:
+		item.Description += "<p>" + html.EscapeString(text) + "</p>"
+	}
+}
+
+// Mutator compiles rules into feed item mutate function, rules are expected to be validated.
+func (r *Rules) Mutator() func(item *feedhlp.Item) *feedhlp.Item {
+	reTitle := cachedregexp.MustCompile(cache, r.TitleQuery)
+	reDescription := cachedregexp.MustCompile(cache, r.DescriptionQuery)
+	reCategory := cachedregexp.MustCompile(cache, r.CategoryQuery)
+
+	reTitleReplace := cachedregexp.MustCompile(cache, r.TitleReplace)
+	reDescriptionReplace := cachedregexp.MustCompile(cache, r.DescriptionReplace)
+	reAuthorReplace := cachedregexp.MustCompile(cache, r.AuthorReplace)
+
+	var filter itemfilter.Expr
+
+	if r.Filter != "" {
+		filter = itemfilter.MustCompile(cache, r.Filter)
+	}
+
+	currentTime := time.Now()
+
+	mark, markText := r.markMode()
+
+	return func(item *feedhlp.Item) *feedhlp.Item {
+		matched := (r.TitleQuery != "" && reTitle.MatchString(item.Title)) ||
+			(r.DescriptionQuery != "" && reDescription.MatchString(item.Description)) ||
+			(r.CategoryQuery != "" && matchAnyCategory(item.Categories, reCategory.MatchString)) ||
+			(filter != nil && filter.Match(filterFields(item)))
+
+		if r.Mode == muteModeKeep && r.hasMatchQuery() {
+			// in keep mode only matched items survive
+			matched = !matched
+		}
+
+		// excluded categories are dropped (or marked) in both modes
+		if len(r.CategoryExclude) > 0 && matchAnyCategory(item.Categories, func(category string) bool {
+			return containsFold(r.CategoryExclude, category)
+		}) {
+			matched = true
+		}
+
+		if matched && mark == "" {
+			// do not add item to resulting feed when
+			// RegExp matched and mark mode not specified
+			return nil
+		}
+
+		if r.TitleReplace != "" {
+			item.Title = reTitleReplace.ReplaceAllString(item.Title, r.TitleWith)
+		}
+
+		if r.DescriptionReplace != "" {
+			item.Description = reDescriptionReplace.ReplaceAllString(item.Description, r.DescriptionWith)
+		}
+
+		if r.AuthorReplace != "" && item.Author != nil {
+			item.Author.Name = reAuthorReplace.ReplaceAllString(item.Author.Name, r.AuthorWith)
+		}
+
+		if matched {
+			markItem(item, mark, markText)
+
+			item.Updated = currentTime
+		}
+
+		return item
+	}
+}
+
+func matchAnyCategory(categories []string, match func(category string) bool) bool {
+	for _, el := range categories {
+		if match(strings.TrimSpace(el)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func containsFold(list []string, value string) bool {
+	for _, el := range list {
+		if strings.EqualFold(strings.TrimSpace(el), value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func filterFields(item *feedhlp.Item) itemfilter.Fields {
+	fields := itemfilter.Fields{
+		itemfilter.FieldTitle:       {item.Title},
+		itemfilter.FieldDescription: {item.Description},
+		itemfilter.FieldContent:     {item.Content},
+		itemfilter.FieldGUID:        {item.Id},
+		itemfilter.FieldCategory:    item.Categories,
+	}
+
+	if item.Link != nil {
+		fields[itemfilter.FieldLink] = []string{item.Link.Href}
+	}
+
+	if item.Author != nil {
+		fields[itemfilter.FieldAuthor] = []string{item.Author.Name, item.Author.Email}
+	}
+
+	return fields
+}
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/jellydator/ttlcache/v3"
+	"github.com/jlelse/feeds"
+	"github.com/s3rj1k/yafp/pkg/feedhlp"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() { //nolint:gochecknoinits // compiled expressions are stored in global cache
+	cache = ttlcache.New[string, any]()
+}
+
+func newTestItems() []*feedhlp.Item {
+	newItem := func(title, description, author string, categories ...string) *feedhlp.Item {
+		item := new(feedhlp.Item)
+
+		item.Title = title
+		item.Description = description
+		item.Author = &feeds.Author{Name: author}
+		item.Link = &feeds.Link{Href: "https://example.com/" + title}
+		item.Categories = categories
+
+		return item
+	}
+
+	return []*feedhlp.Item{
+		newItem("Movie (1999)", "old movie", "alice", "Movies"),
+		newItem("Series S01", "new series", "bob", " Anime ", "Series"),
+		newItem("News", "daily news", "carol"),
+	}
+}
+
+func mutate(r *Rules) []*feedhlp.Item {
+	feed := &feedhlp.Feed{Items: newTestItems()}
+
+	feedhlp.MutateItems(feed, r.Mutator())
+
+	return feed.Items
+}
+
+func titles(items []*feedhlp.Item) []string {
+	out := make([]string, 0, len(items))
+
+	for _, el := range items {
+		out = append(out, el.Title)
+	}
+
+	return out
+}
+
+func TestMutatorFilter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		rules    *Rules
+		expected []string
+	}{
+		{
+			name:     "title query",
+			rules:    &Rules{TitleQuery: `\(19[0-9]{2}\)`},
+			expected: []string{"Series S01", "News"},
+		},
+		{
+			name:     "description query",
+			rules:    &Rules{DescriptionQuery: `(?i)NEWS`},
+			expected: []string{"Movie (1999)", "Series S01"},
+		},
+		{
+			name:     "keep mode",
+			rules:    &Rules{TitleQuery: `\(19[0-9]{2}\)`, Mode: muteModeKeep},
+			expected: []string{"Movie (1999)"},
+		},
+		{
+			name:     "keep mode without query keeps everything",
+			rules:    &Rules{TitleReplace: "News", TitleWith: "Digest", Mode: muteModeKeep},
+			expected: []string{"Movie (1999)", "Series S01", "Digest"},
+		},
+		{
+			name:     "category query",
+			rules:    &Rules{CategoryQuery: `^Anime$`},
+			expected: []string{"Movie (1999)", "News"},
+		},
+		{
+			name:     "category exclude",
+			rules:    &Rules{CategoryExclude: []string{"movies", "sport"}},
+			expected: []string{"Series S01", "News"},
+		},
+		{
+			name:     "category exclude in keep mode",
+			rules:    &Rules{CategoryExclude: []string{"movies"}, Mode: muteModeKeep},
+			expected: []string{"Series S01", "News"},
+		},
+		{
+			name:     "keep mode with category exclude",
+			rules:    &Rules{TitleQuery: `^(Movie|Series)`, CategoryExclude: []string{"anime"}, Mode: muteModeKeep},
+			expected: []string{"Movie (1999)"},
+		},
+		{
+			name:     "filter expression",
+			rules:    &Rules{Filter: `author == "bob" OR category in ["Movies"]`},
+			expected: []string{"News"},
+		},
+		{
+			name:     "keep mode with filter expression",
+			rules:    &Rules{Filter: `NOT description contains "new"`, Mode: muteModeKeep},
+			expected: []string{"Movie (1999)"},
+		},
+		{
+			name:     "title replace with capture group",
+			rules:    &Rules{TitleReplace: `^(.+) \((\d{4})\)$`, TitleWith: "$2: $1"},
+			expected: []string{"1999: Movie", "Series S01", "News"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, titles(mutate(tt.rules)))
+		})
+	}
+}
+
+func TestMutatorReplace(t *testing.T) {
+	t.Parallel()
+
+	items := mutate(&Rules{
+		DescriptionReplace: `(\w+) (\w+)`,
+		DescriptionWith:    "$2 $1",
+		AuthorReplace:      `^(a)lice$`,
+		AuthorWith:         "${1}nn",
+	})
+
+	assert.Len(t, items, 3)
+	assert.Equal(t, "movie old", items[0].Description)
+	assert.Equal(t, "ann", items[0].Author.Name)
+	assert.Equal(t, "bob", items[1].Author.Name)
+}
+
+func TestMutatorMark(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		rules *Rules
+		check func(t *testing.T, marked, other *feedhlp.Item)
+	}{
+		{
+			name:  "author",
+			rules: &Rules{TitleQuery: "Movie", Mark: markAuthor, MarkText: "MUTED"},
+			check: func(t *testing.T, marked, other *feedhlp.Item) {
+				t.Helper()
+
+				assert.Equal(t, "MUTED", marked.Author.Name)
+				assert.Equal(t, "bob", other.Author.Name)
+			},
+		},
+		{
+			name:  "title with default text",
+			rules: &Rules{TitleQuery: "Movie", Mark: markTitle},
+			check: func(t *testing.T, marked, other *feedhlp.Item) {
+				t.Helper()
+
+				assert.Equal(t, defaultMarkText+" Movie (1999)", marked.Title)
+				assert.Equal(t, "Series S01", other.Title)
+			},
+		},
+		{
+			name:  "category",
+			rules: &Rules{TitleQuery: "Movie", Mark: markCategory, MarkText: "muted"},
+			check: func(t *testing.T, marked, other *feedhlp.Item) {
+				t.Helper()
+
+				assert.Equal(t, []string{"Movies", "muted"}, marked.Categories)
+				assert.Equal(t, []string{" Anime ", "Series"}, other.Categories)
+			},
+		},
+		{
+			name:  "description",
+			rules: &Rules{TitleQuery: "Movie", Mark: markDescription, MarkText: "<muted>"},
+			check: func(t *testing.T, marked, other *feedhlp.Item) {
+				t.Helper()
+
+				assert.Equal(t, "old movie<p>&lt;muted&gt;</p>", marked.Description)
+				assert.Equal(t, "new series", other.Description)
+			},
+		},
+		{
+			name:  "rewrite author alias",
+			rules: &Rules{TitleQuery: "Movie", RewriteAuthor: "MUTED"},
+			check: func(t *testing.T, marked, other *feedhlp.Item) {
+				t.Helper()
+
+				assert.Equal(t, "MUTED", marked.Author.Name)
+				assert.Equal(t, "bob", other.Author.Name)
+			},
+		},
+		{
+			name:  "rewrite author text with explicit mark",
+			rules: &Rules{TitleQuery: "Movie", RewriteAuthor: "MUTED", Mark: markTitle},
+			check: func(t *testing.T, marked, other *feedhlp.Item) {
+				t.Helper()
+
+				assert.Equal(t, "MUTED Movie (1999)", marked.Title)
+				assert.Equal(t, "alice", marked.Author.Name)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			items := mutate(tt.rules)
+
+			// marked items are kept
+			assert.Len(t, items, 3)
+
+			tt.check(t, items[0], items[1])
+
+			assert.False(t, items[0].Updated.IsZero())
+			assert.True(t, items[1].Updated.IsZero())
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jlelse/feeds"
+	"github.com/s3rj1k/yafp/pkg/feedhlp"
+	"github.com/s3rj1k/yafp/pkg/htmlscrapper"
+	"github.com/s3rj1k/yafp/pkg/upstream"
+	"github.com/s3rj1k/yafp/pkg/validation"
+)
+
+const maxNumberOfScrapePages = 5
+
+type Scrape struct {
+	URL      string `form:"url" json:"url" binding:"required,url"`
+	Upstream string `form:"upstream" json:"upstream"`
+
+	htmlscrapper.Selectors
+	Rules
+	FeedOptions
+}
+
+type ScraperName struct {
+	Name string `uri:"name" binding:"required"`
+}
+
+func scrapeProperURLQueryParamsName() *strings.Replacer {
+	return properURLQueryParamsName(
+		"URL", "url",
+		"Upstream", "upstream",
+		"Item", "item",
+		"Title", "title",
+		"Link", "link",
+		"Date", "date",
+		"Body", "body",
+		"Next", "next",
+	)
+}
+
+func handleScrape(c *gin.Context) {
+	cfg := new(Scrape)
+
+	if err := c.BindQuery(cfg); err != nil {
+		c.String(http.StatusBadRequest, "%s\n",
+			validation.ErrorResponse(err, scrapeProperURLQueryParamsName()),
+		)
+
+		return
+	}
+
+	serveScrape(c, cfg)
+}
+
+// handleScraper serves named scraper from configuration.
+func handleScraper(c *gin.Context) {
+	cfg := new(ScraperName)
+
+	if err := c.BindUri(cfg); err != nil {
+		c.String(http.StatusBadRequest, "invalid scraper name\n")
+
+		return
+	}
+
+	scrape, ok := config.Scrapers[cfg.Name]
+	if !ok || scrape == nil {
+		c.String(http.StatusNotFound, "%d Not Found\n", http.StatusNotFound)
+
+		return
+	}
+
+	serveScrape(c, scrape)
+}
+
+func serveScrape(c *gin.Context, cfg *Scrape) {
+	opts, ok := bindUpstream(c, cfg.Upstream)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), feedFetchTimeoutSeconds*time.Second)
+	defer cancel()
+
+	feedOut, err := fetchScrape(ctx, cfg, c.Request.UserAgent(), opts)
+	if err != nil {
+		c.String(http.StatusServiceUnavailable,
+			"%d Unable to scrape page", http.StatusServiceUnavailable)
+
+		_ = c.Error(err)
+
+		return
+	}
+
+	processItems(feedOut, archiveKindScrape, fmt.Sprintf("%s %v", cfg.URL, cfg.Selectors), opts,
+		cfg.History, cfg.Rules.Mutator())
+
+	serveFeed(c, feedOut, &cfg.FeedOptions, feedhlp.ContentTypeRSS, nil)
+}
+
+// fetchScrape scrapes page and following pages (when next page selector is defined)
+// into feed, items are identified by link and repeated links are skipped.
+func fetchScrape(ctx context.Context, cfg *Scrape, userAgent string, opts *upstream.Options) (*feedhlp.Feed, error) {
+	feed := new(feedhlp.Feed)
+
+	feed.Link = &feeds.Link{
+		Href: cfg.URL,
+	}
+	feed.Updated = time.Now().UTC()
+
+	feed.Items = make([]*feedhlp.Item, 0)
+
+	seen := make(map[string]bool)
+	pageURL := cfg.URL
+
+	for i := 0; i < maxNumberOfScrapePages && pageURL != "" && !seen[pageURL]; i++ {
+		seen[pageURL] = true
+
+		doc, err := fetchDocument(ctx, pageURL, userAgent, opts)
+		if err != nil {
+			if i > 0 {
+				break // keep items from already scraped pages
+			}
+
+			return nil, err
+		}
+
+		page, err := htmlscrapper.Parse(doc, &cfg.Selectors)
+		if err != nil {
+			if i > 0 {
+				break
+			}
+
+			return nil, err //nolint:wrapcheck // pass htmlscrapper error unwrapped
+		}
+
+		if i == 0 {
+			feed.Title = page.Title
+			feed.Description = page.Description
+		}
+
+		for _, el := range page.Items {
+			link := el.Link
+			if link == "" {
+				link = pageURL
+			}
+
+			id := link + "\n" + el.Title
+			if seen[id] {
+				continue
+			}
+
+			seen[id] = true
+
+			item := new(feedhlp.Item)
+
+			item.Id = link
+			item.Title = el.Title
+			item.Description = el.Body
+			item.Link = &feeds.Link{
+				Href: link,
+			}
+
+			if !el.Date.IsZero() {
+				item.Created = el.Date
+				item.Updated = el.Date
+			}
+
+			feed.Items = append(feed.Items, item)
+		}
+
+		pageURL = page.Next
+	}
+
+	if feed.Title == "" {
+		feed.Title = cfg.URL
+	}
+
+	return feed, nil
+}
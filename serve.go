@@ -0,0 +1,36 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/s3rj1k/yafp/pkg/feedhlp"
+)
+
+// serveFeed applies feed options and writes feed in selected format, fallback content type
+// is used when output format is neither set nor negotiated, populate (may be nil)
+// completes items content before HTML is finalized.
+func serveFeed(
+	c *gin.Context, feed *feedhlp.Feed, options *FeedOptions,
+	fallbackContentType string, populate func(feed *feedhlp.Feed),
+) {
+	options.Apply(feed)
+
+	if populate != nil {
+		populate(feed)
+	}
+
+	options.Finalize(feed, imageProxy(c))
+
+	contentType := options.ContentType(c.GetHeader("Accept"), fallbackContentType)
+
+	out, err := feedhlp.RenderFeedBasedOnProvidedContentType(feed, contentType)
+	if err != nil {
+		c.String(http.StatusServiceUnavailable,
+			"%d Unable to build feed", http.StatusServiceUnavailable)
+
+		return
+	}
+
+	c.Data(http.StatusOK, contentType, []byte(out))
+}
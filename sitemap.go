@@ -0,0 +1,216 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/gin-gonic/gin"
+	"github.com/jlelse/feeds"
+	"github.com/s3rj1k/yafp/pkg/feedhlp"
+	"github.com/s3rj1k/yafp/pkg/sitemap"
+	"github.com/s3rj1k/yafp/pkg/upstream"
+	"github.com/s3rj1k/yafp/pkg/validation"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	maxNumberOfSitemaps     = 10
+	maxNumberOfSitemapItems = 50
+
+	sitemapConcurrency = 4
+
+	pageTitleCacheKeyPrefix = "PAGETITLE:"
+)
+
+type Sitemap struct {
+	URL      string `form:"url" json:"url" binding:"required,url"`
+	Titles   bool   `form:"titles" json:"titles"`
+	Upstream string `form:"upstream" json:"upstream"`
+
+	Rules
+	FeedOptions
+}
+
+func sitemapProperURLQueryParamsName() *strings.Replacer {
+	return properURLQueryParamsName(
+		"URL", "url",
+		"Titles", "titles",
+		"Upstream", "upstream",
+	)
+}
+
+func handleSitemap(c *gin.Context) {
+	cfg := new(Sitemap)
+
+	if err := c.BindQuery(cfg); err != nil {
+		c.String(http.StatusBadRequest, "%s\n",
+			validation.ErrorResponse(err, sitemapProperURLQueryParamsName()),
+		)
+
+		return
+	}
+
+	opts, ok := bindUpstream(c, cfg.Upstream)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), feedFetchTimeoutSeconds*time.Second)
+	defer cancel()
+
+	feedOut, err := fetchSitemapFeed(ctx, cfg, c.Request.UserAgent(), opts)
+	if err != nil {
+		c.String(http.StatusServiceUnavailable,
+			"%d Unable to read sitemap", http.StatusServiceUnavailable)
+
+		_ = c.Error(err)
+
+		return
+	}
+
+	processItems(feedOut, archiveKindSitemap, cfg.URL, opts, cfg.History, cfg.Rules.Mutator())
+
+	serveFeed(c, feedOut, &cfg.FeedOptions, feedhlp.ContentTypeRSS, nil)
+}
+
+// getPageTitle returns `og:title` or `<title>` of linked page, see `getPageValue`.
+func getPageTitle(ctx context.Context, link, userAgent string, opts *upstream.Options) string {
+	return getPageValue(ctx, pageTitleCacheKeyPrefix, link, userAgent, opts, func(doc *goquery.Document) string {
+		if title, err := sitemap.GetPageOpenGraphTitle(doc.Selection); err == nil {
+			return title
+		}
+
+		if title, err := sitemap.GetPageTitle(doc.Selection); err == nil {
+			return title
+		}
+
+		return ""
+	})
+}
+
+// fetchSitemapEntries returns page entries of sitemap, for sitemap index
+// the most recently modified child sitemaps are read (nested indexes are ignored).
+func fetchSitemapEntries(ctx context.Context, sitemapURL, userAgent string, opts *upstream.Options) ([]sitemap.Entry, error) {
+	root, err := fetchSitemap(ctx, sitemapURL, userAgent, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(root.Sitemaps) == 0 {
+		return root.URLs, nil
+	}
+
+	sitemap.SortByLastMod(root.Sitemaps)
+
+	if len(root.Sitemaps) > maxNumberOfSitemaps {
+		root.Sitemaps = root.Sitemaps[:maxNumberOfSitemaps]
+	}
+
+	children := make([][]sitemap.Entry, len(root.Sitemaps))
+
+	g := new(errgroup.Group)
+
+	g.SetLimit(sitemapConcurrency)
+
+	for i, el := range root.Sitemaps {
+		i, loc := i, el.Loc
+
+		g.Go(func() error {
+			child, err := fetchSitemap(ctx, loc, userAgent, opts)
+			if err != nil {
+				return nil // failed child sitemap is skipped
+			}
+
+			children[i] = child.URLs
+
+			return nil
+		})
+	}
+
+	_ = g.Wait()
+
+	entries := root.URLs
+
+	for _, el := range children {
+		entries = append(entries, el...)
+	}
+
+	return entries, nil
+}
+
+// fetchSitemapFeed converts sitemap entries to feed items, newest first.
+func fetchSitemapFeed(ctx context.Context, cfg *Sitemap, userAgent string, opts *upstream.Options) (*feedhlp.Feed, error) {
+	entries, err := fetchSitemapEntries(ctx, cfg.URL, userAgent, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	sitemap.SortByLastMod(entries)
+
+	if len(entries) > maxNumberOfSitemapItems {
+		entries = entries[:maxNumberOfSitemapItems]
+	}
+
+	if cfg.Titles {
+		g := new(errgroup.Group)
+
+		g.SetLimit(sitemapConcurrency)
+
+		for i := range entries {
+			entry := &entries[i]
+
+			if entry.Title != "" {
+				continue
+			}
+
+			g.Go(func() error {
+				entry.Title = getPageTitle(ctx, entry.Loc, userAgent, opts)
+
+				return nil
+			})
+		}
+
+		_ = g.Wait()
+	}
+
+	feed := new(feedhlp.Feed)
+
+	feed.Title = cfg.URL
+	if u, err := url.Parse(cfg.URL); err == nil {
+		feed.Title = u.Host
+	}
+
+	feed.Link = &feeds.Link{
+		Href: cfg.URL,
+	}
+	feed.Updated = time.Now().UTC()
+
+	feed.Items = make([]*feedhlp.Item, 0, len(entries))
+
+	for _, el := range entries {
+		item := new(feedhlp.Item)
+
+		item.Id = el.Loc
+		item.Title = el.Title
+		if item.Title == "" {
+			item.Title = el.Loc
+		}
+
+		item.Link = &feeds.Link{
+			Href: el.Loc,
+		}
+
+		if !el.LastMod.IsZero() {
+			item.Created = el.LastMod
+			item.Updated = el.LastMod
+		}
+
+		feed.Items = append(feed.Items, item)
+	}
+
+	return feed, nil
+}
@@ -3,18 +3,20 @@ package main
 import (
 	"context"
 	"net/http"
-	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/jlelse/feeds"
 	"github.com/s3rj1k/yafp/pkg/capitalise"
 	"github.com/s3rj1k/yafp/pkg/feedhlp"
-	"github.com/s3rj1k/yafp/pkg/tgscrapper"
+	"github.com/s3rj1k/yafp/pkg/validation"
 )
 
 type TG struct {
-	Name string `uri:"name" binding:"required,tg"`
+	Name     string `uri:"name" binding:"required,tg"`
+	Upstream string `form:"upstream" json:"upstream"`
+
+	FeedOptions
 }
 
 func handleTG(c *gin.Context) {
@@ -26,66 +28,33 @@ func handleTG(c *gin.Context) {
 		return
 	}
 
-	data := tgscrapper.NewMessages(cfg.Name)
-
-	ctx, cancel := context.WithTimeout(context.Background(), feedFetchTimeoutSeconds*time.Second)
-	defer cancel()
-
-	err := tgscrapper.Worker(ctx, data, c.Request.UserAgent())
-	if err != nil {
-		c.String(http.StatusServiceUnavailable,
-			"%d %s", http.StatusServiceUnavailable, capitalise.First(err.Error()))
+	if err := c.BindQuery(cfg); err != nil {
+		c.String(http.StatusBadRequest, "%s\n",
+			validation.ErrorResponse(err, strings.NewReplacer(append(feedOptionsProperURLQueryParamsName(),
+				"Upstream", "upstream",
+			)...)),
+		)
 
 		return
 	}
 
-	contentType := feedhlp.ContentTypeRSS
-
-	feedOut := &feeds.Feed{
-		Title: data.ChannelTitle,
-		Link: &feeds.Link{
-			Href: data.ChannelLink,
-		},
-		Description: data.ChannelDescription,
-		Updated:     data.GenerationTime,
-		Created:     data.OldestMessageDate,
+	opts, ok := bindUpstream(c, cfg.Upstream)
+	if !ok {
+		return
 	}
 
-	feedOut.Items = make([]*feeds.Item, 0, len(data.Items))
-
-	for _, el := range data.Items {
-		if el == nil {
-			continue
-		}
-
-		item := new(feeds.Item)
-
-		item.Id = strconv.Itoa(el.ID)
-		item.Title = el.Title
-		item.Description = el.Body
-		item.Link = &feeds.Link{
-			Href: el.Link,
-		}
-
-		if !el.DateTime.IsZero() {
-			item.Created = el.DateTime
-			item.Updated = el.DateTime
-		}
-
-		item.Author = &feeds.Author{
-			Name: el.Author,
-		}
-
-		feedOut.Items = append(feedOut.Items, item)
-	}
+	ctx, cancel := context.WithTimeout(context.Background(), feedFetchTimeoutSeconds*time.Second)
+	defer cancel()
 
-	out, err := feedhlp.RenderFeedBasedOnProvidedContentType(feedOut, contentType)
+	feedOut, err := fetchTGChannel(ctx, cfg.Name, c.Request.UserAgent(), opts)
 	if err != nil {
 		c.String(http.StatusServiceUnavailable,
-			"%d Unable to build feed", http.StatusServiceUnavailable)
+			"%d %s", http.StatusServiceUnavailable, capitalise.First(err.Error()))
 
 		return
 	}
 
-	c.Data(http.StatusOK, contentType, []byte(out))
+	processItems(feedOut, archiveKindTG, cfg.Name, opts, cfg.History, nil)
+
+	serveFeed(c, feedOut, &cfg.FeedOptions, feedhlp.ContentTypeRSS, nil)
 }
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+	"github.com/s3rj1k/yafp/pkg/upstream"
+)
+
+// bindUpstream returns named upstream options from configuration, empty name
+// means that requests are not customized, on failure response is already written.
+func bindUpstream(c *gin.Context, name string) (*upstream.Options, bool) {
+	if name == "" {
+		return nil, true
+	}
+
+	opts, ok := config.Upstreams[name]
+	if !ok || opts == nil {
+		c.String(http.StatusBadRequest, "%d Unknown upstream\n", http.StatusBadRequest)
+
+		return nil, false
+	}
+
+	return opts, true
+}
+
+// newHTTPClient returns client for outbound requests, proxy URL (may be empty)
+// is used for every host that has no own proxy in configuration.
+func newHTTPClient(proxyURL string, proxies map[string]string) (*http.Client, error) {
+	var (
+		defaultProxy *url.URL
+		err          error
+	)
+
+	if proxyURL != "" {
+		defaultProxy, err = upstream.ParseProxyURL(proxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy: %w", err)
+		}
+	}
+
+	hosts := make(map[string]*url.URL, len(proxies))
+
+	for host, val := range proxies {
+		hosts[host], err = upstream.ParseProxyURL(val)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy for %q: %w", host, err)
+		}
+	}
+
+	return upstream.NewClient(upstream.ProxyFunc(defaultProxy, hosts)), nil
+}
@@ -2,9 +2,14 @@ package main
 
 import (
 	"regexp"
+	"time"
 
+	"github.com/andybalholm/cascadia"
+	"github.com/araddon/dateparse"
 	"github.com/go-playground/validator/v10"
 	"github.com/s3rj1k/yafp/pkg/cachedregexp"
+	"github.com/s3rj1k/yafp/pkg/itemfilter"
+	"github.com/s3rj1k/yafp/pkg/jsonmapper"
 )
 
 // https://core.telegram.org/method/account.checkUsername
@@ -27,6 +32,89 @@ func ValidateRegularExpression(fl validator.FieldLevel) bool {
 	return true
 }
 
+func ValidateFilterExpression(fl validator.FieldLevel) bool {
+	expr, ok := fl.Field().Interface().(string)
+	if !ok {
+		return false
+	}
+
+	if expr == "" {
+		return true // empty filter is allowed, at least one rule is required by `ValidateMute`
+	}
+
+	if _, err := itemfilter.Compile(cache, expr); err != nil {
+		return false
+	}
+
+	return true
+}
+
+func ValidateCSSSelector(fl validator.FieldLevel) bool {
+	selector, ok := fl.Field().Interface().(string)
+	if !ok {
+		return false
+	}
+
+	if _, err := cascadia.Compile(selector); err != nil {
+		return false
+	}
+
+	return true
+}
+
+func ValidateJSONPath(fl validator.FieldLevel) bool {
+	expr, ok := fl.Field().Interface().(string)
+	if !ok {
+		return false
+	}
+
+	if _, err := jsonmapper.CompilePath(expr); err != nil {
+		return false
+	}
+
+	return true
+}
+
+func ValidateDuration(fl validator.FieldLevel) bool {
+	value, ok := fl.Field().Interface().(string)
+	if !ok {
+		return false
+	}
+
+	val, err := time.ParseDuration(value)
+	if err != nil {
+		return false
+	}
+
+	return val >= 0
+}
+
+func ValidateDate(fl validator.FieldLevel) bool {
+	value, ok := fl.Field().Interface().(string)
+	if !ok {
+		return false
+	}
+
+	if _, err := dateparse.ParseAny(value); err != nil {
+		return false
+	}
+
+	return true
+}
+
+// ValidateMute requires at least one query or replace rule to be defined,
+// explicit pass-through serves upstream feed with feed options only.
+func ValidateMute(sl validator.StructLevel) {
+	m, ok := sl.Current().Interface().(Mute)
+	if !ok {
+		return
+	}
+
+	if !m.Passthrough && !m.hasQuery() && !m.hasReplace() {
+		sl.ReportError(m.TitleQuery, "TitleQuery", "TitleQuery", "required_rule", "")
+	}
+}
+
 func ValidateTGChannelName(fl validator.FieldLevel) bool {
 	value, ok := fl.Field().Interface().(string)
 	if !ok {
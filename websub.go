@@ -0,0 +1,222 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/jellydator/ttlcache/v3"
+	"github.com/mmcdole/gofeed"
+	"github.com/s3rj1k/yafp/pkg/gincache"
+	"github.com/s3rj1k/yafp/pkg/ratelimit"
+	"github.com/s3rj1k/yafp/pkg/validation"
+	"github.com/s3rj1k/yafp/pkg/websub"
+	"golang.org/x/time/rate"
+)
+
+const (
+	webSubUserAgent = "yafp (WebSub hub)"
+
+	webSubMaxTopics            = 100
+	webSubMaxSubscriptions     = 1000
+	webSubMaxHostSubscriptions = 100
+
+	webSubTopicCacheKeyPrefix = "WEBSUB:"
+)
+
+//nolint:gochecknoglobals // WebSub hub, nil when disabled
+var (
+	hub *websub.Hub
+)
+
+type WebSub struct {
+	Mode         string `form:"hub.mode" binding:"required,oneof=subscribe unsubscribe"`
+	Topic        string `form:"hub.topic" binding:"required,url"`
+	Callback     string `form:"hub.callback" binding:"required,url"`
+	Secret       string `form:"hub.secret" binding:"max=200"`
+	LeaseSeconds int    `form:"hub.lease_seconds" binding:"min=0"`
+}
+
+// initWebSubHub starts hub that polls topics with provided interval,
+// topics are rendered by provided handler (router) without network round trip.
+func initWebSubHub(handler http.Handler, interval time.Duration) error {
+	base := strings.TrimSuffix(flagPublicURL, "/")
+	if base == "" {
+		return fmt.Errorf("WebSub hub requires public URL")
+	}
+
+	// internal requests share no client address and are rate limited together,
+	// topics are polled no faster than that limit allows
+	limiter := rate.NewLimiter(rate.Every(ratelimit.DefaultInterval), 1)
+
+	hub = websub.NewHub(base+"/websub", func(ctx context.Context, topic string) (string, []byte, error) {
+		if err := limiter.Wait(ctx); err != nil {
+			return "", nil, fmt.Errorf("rate limit error: %w", err)
+		}
+
+		// topic is rendered from fresh upstream content and replaces cached response
+		ctx = gincache.WithRefresh(ctx)
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, topic, http.NoBody)
+		if err != nil {
+			return "", nil, fmt.Errorf("prepare request error: %w", err)
+		}
+
+		req.RequestURI = req.URL.RequestURI()
+		req.Header.Set("User-Agent", webSubUserAgent)
+
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK {
+			return "", nil, fmt.Errorf("unexpected status code: %d", rec.Code)
+		}
+
+		return rec.Header().Get("Content-Type"), rec.Body.Bytes(), nil
+	})
+
+	hub.Client = httpClient
+	hub.Digest = feedDigest
+	hub.MaxTopics = webSubMaxTopics
+	hub.MaxSubscriptions = webSubMaxSubscriptions
+	hub.MaxHostSubscriptions = webSubMaxHostSubscriptions
+
+	go hub.Run(context.Background(), interval)
+
+	return nil
+}
+
+// feedDigest ignores feed build time and item update time (it is set on render by `mark`),
+// only changed items (or feed title) are pushed.
+func feedDigest(body []byte) []byte {
+	feed, err := gofeed.NewParser().Parse(bytes.NewReader(body))
+	if err != nil {
+		sum := sha256.Sum256(body)
+
+		return sum[:]
+	}
+
+	h := sha256.New()
+
+	_, _ = fmt.Fprintln(h, feed.Title)
+
+	for _, item := range feed.Items {
+		_, _ = fmt.Fprintln(h, item.GUID, item.Link, item.Title, item.Published)
+		_, _ = fmt.Fprintln(h, item.Description, item.Content)
+	}
+
+	return h.Sum(nil)
+}
+
+// advertiseWebSubHub adds `Link` header with hub and topic URLs to feed responses,
+// served feed is recorded as topic that can be subscribed to.
+func advertiseWebSubHub(c *gin.Context) {
+	if hub == nil {
+		c.Next()
+
+		return
+	}
+
+	topic := publicBaseURL(c) + c.Request.URL.RequestURI()
+
+	c.Header("Link", websub.LinkHeader(hub.URL, topic))
+	c.Next()
+
+	if c.Writer.Status() == http.StatusOK {
+		cache.Set(webSubTopicCacheKeyPrefix+topic, true, ttlcache.DefaultTTL)
+	}
+}
+
+// isWebSubTopic reports whether topic is configured feed (profile, scraper or JSON source)
+// or feed that was recently served, other URLs of this service are not polled.
+func isWebSubTopic(base, topic string) bool {
+	if !strings.HasPrefix(topic, base+"/") {
+		return false
+	}
+
+	if item := cache.Get(webSubTopicCacheKeyPrefix + topic); item != nil {
+		return true
+	}
+
+	u, err := url.Parse(strings.TrimPrefix(topic, base))
+	if err != nil || u.RawQuery != "" {
+		return false
+	}
+
+	var ok bool
+
+	switch route, name := path.Split(u.Path); route {
+	case "/p/":
+		_, ok = config.Profiles[name]
+	case "/scrape/":
+		_, ok = config.Scrapers[name]
+	case "/json/":
+		_, ok = config.JSON[name]
+	}
+
+	return ok
+}
+
+// handleWebSub accepts subscription requests, intent of subscriber is verified asynchronously.
+func handleWebSub(c *gin.Context) {
+	if hub == nil {
+		c.String(http.StatusNotFound, "%d Not Found\n", http.StatusNotFound)
+
+		return
+	}
+
+	cfg := new(WebSub)
+
+	if err := c.ShouldBindWith(cfg, binding.FormPost); err != nil {
+		c.String(http.StatusBadRequest, "%s\n",
+			validation.ErrorResponse(err, strings.NewReplacer(
+				"Mode", "hub.mode",
+				"Topic", "hub.topic",
+				"Callback", "hub.callback",
+				"Secret", "hub.secret",
+				"LeaseSeconds", "hub.lease_seconds",
+			)),
+		)
+
+		return
+	}
+
+	// only feeds of this service are topics, existing topics can always be unsubscribed from
+	if hub.Subscribers(cfg.Topic) == 0 && !isWebSubTopic(publicBaseURL(c), cfg.Topic) {
+		c.String(http.StatusBadRequest, "%d Unknown topic\n", http.StatusBadRequest)
+
+		return
+	}
+
+	req := &websub.Request{
+		Mode:     cfg.Mode,
+		Topic:    cfg.Topic,
+		Callback: cfg.Callback,
+		Secret:   cfg.Secret,
+		Lease:    time.Duration(cfg.LeaseSeconds) * time.Second,
+	}
+
+	if err := hub.Check(req); err != nil {
+		c.String(http.StatusTooManyRequests, "%d Too many subscriptions\n", http.StatusTooManyRequests)
+
+		return
+	}
+
+	go func() {
+		if err := hub.Verify(context.Background(), req); err != nil {
+			fmt.Fprintf(gin.DefaultErrorWriter, "[WebSub] %s %s: %s\n", req.Mode, req.Callback, err.Error())
+		}
+	}()
+
+	c.String(http.StatusAccepted, "%d Accepted\n", http.StatusAccepted)
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsWebSubTopic(t *testing.T) {
+	const base = "https://yafp.example.com"
+
+	config = &Config{
+		Profiles: map[string]*Profile{"news": new(Profile)},
+		Scrapers: map[string]*Scrape{"blog": new(Scrape)},
+	}
+
+	cache.Set(webSubTopicCacheKeyPrefix+base+"/tg/channel", true, 0)
+
+	tests := []struct {
+		topic    string
+		expected bool
+	}{
+		{topic: base + "/p/news", expected: true},
+		{topic: base + "/scrape/blog", expected: true},
+		{topic: base + "/tg/channel", expected: true},
+		{topic: base + "/p/news?x=1", expected: false},
+		{topic: base + "/p/other", expected: false},
+		{topic: base + "/json/blog", expected: false},
+		{topic: base + "/mute?feed_url=https://example.com/feed", expected: false},
+		{topic: "https://example.com/p/news", expected: false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, isWebSubTopic(base, tt.topic), tt.topic)
+	}
+}